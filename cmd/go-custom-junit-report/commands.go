@@ -0,0 +1,292 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/arquivei/go-custom-junit-report/report"
+)
+
+// newFlagSet returns a flag set for the given command with the -state flag
+// already defined
+func newFlagSet(name string, state *string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.StringVar(state, "state", defaultStateFile, "report state file")
+	return flags
+}
+
+// formats maps the values of the render -format flag to their formatters
+var formats = map[string]report.Formatter{
+	"xml":         report.IndentedXML,
+	"compact-xml": report.CompactXML,
+	"json":        report.JSON,
+	"tap":         report.TAP,
+	"markdown":    report.Markdown,
+	"html":        report.HTML,
+	"sarif":       report.SARIF,
+	"codequality": report.CodeQuality,
+}
+
+// saveState writes the report to the state file. The state is always XML so it
+// can be loaded again, whatever the state file extension is.
+func saveState(suites *report.TestSuites, state string) error {
+	return suites.SaveReport(state, report.WithFormatter(report.IndentedXML))
+}
+
+func runInit(args []string, _ io.Reader, _ io.Writer) error {
+	var state, id, name string
+	flags := newFlagSet("init", &state)
+	flags.StringVar(&id, "id", "", "report ID")
+	flags.StringVar(&name, "name", "", "report name")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	return saveState(report.NewTestSuites(id, name), state)
+}
+
+func runAddSuite(args []string, _ io.Reader, _ io.Writer) error {
+	var state, id, name, pkg string
+	var hostname bool
+	flags := newFlagSet("add-suite", &state)
+	flags.StringVar(&id, "id", "", "suite ID, must be unique in the report")
+	flags.StringVar(&name, "name", "", "suite name")
+	flags.StringVar(&pkg, "package", "", "package of the tested code")
+	flags.BoolVar(&hostname, "hostname", false, "record the name of the current host")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	suites, err := report.LoadReport(state)
+	if err != nil {
+		return err
+	}
+
+	var opts []report.SuiteOption
+	if hostname {
+		opts = append(opts, report.WithHostname())
+	}
+
+	// Cases added from the shell are never started, so the suite is considered
+	// started when it is added
+	suite := report.NewTestSuite(id, name, opts...)
+	suite.Package = pkg
+	suite.Timestamp = report.NewTimestamp(time.Now())
+
+	if err := suites.AddTestSuite(suite); err != nil {
+		return err
+	}
+
+	return saveState(suites, state)
+}
+
+func runAddCase(args []string, stdin io.Reader, _ io.Writer) error {
+	var (
+		state, suiteID, id, name, classname, content, skip string
+		file                                               string
+		systemOut, systemErr                               string
+		failure, failureType, failureContent, failureFile  string
+		errorMessage, errorType, errorContent              string
+		line, failureLine                                  int
+		duration                                           time.Duration
+		hasFailure, hasError                               bool
+	)
+
+	flags := newFlagSet("add-case", &state)
+	flags.StringVar(&suiteID, "suite", "", "ID of the suite, defaults to the last suite")
+	flags.StringVar(&id, "id", "", "case ID, must be unique in the suite")
+	flags.StringVar(&name, "name", "", "case name")
+	flags.StringVar(&classname, "classname", "", "case class name")
+	flags.StringVar(&file, "file", "", "source file of the case")
+	flags.IntVar(&line, "line", 0, "source line of the case")
+	flags.DurationVar(&duration, "time", 0, "case duration, eg: 1.5s")
+	flags.StringVar(&content, "content", "", "case output, - reads from stdin")
+	flags.StringVar(&systemOut, "system-out", "", "case standard output, - reads from stdin")
+	flags.StringVar(&systemErr, "system-err", "", "case standard error, - reads from stdin")
+	flags.StringVar(&skip, "skip", "", "mark the case as skipped with the given message")
+	flags.StringVar(&failure, "failure", "", "add a failure with the given message")
+	flags.StringVar(&failureType, "failure-type", "", "failure type")
+	flags.StringVar(&failureContent, "failure-content", "", "failure output, - reads from stdin")
+	flags.StringVar(&failureFile, "failure-file", "", "source file the failure refers to")
+	flags.IntVar(&failureLine, "failure-line", 0, "source line the failure refers to")
+	flags.StringVar(&errorMessage, "error", "", "add an error with the given message")
+	flags.StringVar(&errorType, "error-type", "", "error type")
+	flags.StringVar(&errorContent, "error-content", "", "error output, - reads from stdin")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "failure", "failure-type", "failure-content", "failure-file", "failure-line":
+			hasFailure = true
+		case "error", "error-type", "error-content":
+			hasError = true
+		}
+	})
+
+	var fromStdin *string
+	for _, value := range []*string{&content, &systemOut, &systemErr, &failureContent, &errorContent} {
+		if *value != "-" {
+			continue
+		}
+
+		if fromStdin != nil {
+			return errors.New("only one of -content, -system-out, -system-err, -failure-content, and -error-content can read from stdin")
+		}
+		fromStdin = value
+	}
+
+	if fromStdin != nil {
+		b, err := io.ReadAll(stdin)
+		if err != nil {
+			return err
+		}
+		*fromStdin = string(b)
+	}
+
+	suites, err := report.LoadReport(state)
+	if err != nil {
+		return err
+	}
+
+	suite, err := findSuite(suites, suiteID)
+	if err != nil {
+		return err
+	}
+
+	testCase := report.NewTestCase(id, name, classname)
+	testCase.SetLocation(file, line)
+	testCase.Time = report.Duration(duration)
+	testCase.SetContent(content)
+	testCase.SystemOut = systemOut
+	testCase.SystemErr = systemErr
+
+	if len(skip) > 0 {
+		testCase.Skip(skip)
+	}
+
+	if hasFailure {
+		f := report.NewFailure(failure, failureType, failureContent)
+		f.SetLocation(failureFile, failureLine)
+		testCase.AddFailure(f)
+	}
+
+	if hasError {
+		testCase.AddError(report.NewError(errorMessage, errorType, errorContent))
+	}
+
+	if err := suite.AddTestCase(testCase); err != nil {
+		return err
+	}
+
+	return saveState(suites, state)
+}
+
+func runMerge(args []string, _ io.Reader, _ io.Writer) error {
+	var state, policyName string
+	flags := newFlagSet("merge", &state)
+	flags.StringVar(&policyName, "policy", "fail", "suite ID collision policy: fail, rename, or merge")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	policy, err := parsePolicy(policyName)
+	if err != nil {
+		return err
+	}
+
+	suites, err := report.LoadReport(state)
+	if err != nil {
+		return err
+	}
+
+	for _, filename := range flags.Args() {
+		other, err := report.LoadReport(filename)
+		if err != nil {
+			return err
+		}
+
+		if err := suites.Merge(other, policy); err != nil {
+			return fmt.Errorf("%s: %w", filename, err)
+		}
+	}
+
+	return saveState(suites, state)
+}
+
+func runRender(args []string, _ io.Reader, stdout io.Writer) error {
+	var state, output, flatten, format string
+	flags := newFlagSet("render", &state)
+	flags.StringVar(&output, "o", "-", "output file, - writes to stdout")
+	flags.StringVar(&format, "format", "", "output format: xml, compact-xml, json, tap, markdown, html, sarif or codequality, defaults to the output file extension")
+	flags.StringVar(&flatten, "flatten", "", "move nested suites to the top level, joining names with the given separator")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	formatter := report.FormatterForFile(output)
+	if len(format) > 0 {
+		var ok bool
+		if formatter, ok = formats[format]; !ok {
+			return fmt.Errorf("unknown format %q", format)
+		}
+	}
+
+	suites, err := report.LoadReport(state)
+	if err != nil {
+		return err
+	}
+
+	if len(flatten) > 0 {
+		suites = suites.Flatten(flatten)
+	}
+
+	if output != "-" {
+		return suites.SaveReport(output, report.WithFormatter(formatter))
+	}
+
+	content, err := formatter.Format(suites)
+	if err != nil {
+		return err
+	}
+
+	_, err = stdout.Write(content)
+	return err
+}
+
+// findSuite returns the suite with the given id or the last suite if id is
+// empty
+func findSuite(suites *report.TestSuites, id string) (*report.TestSuite, error) {
+	if len(suites.TestSuites) == 0 {
+		return nil, errors.New("the report has no suites, run add-suite first")
+	}
+
+	if len(id) == 0 {
+		return suites.TestSuites[len(suites.TestSuites)-1], nil
+	}
+
+	for _, suite := range suites.TestSuites {
+		if suite.ID == id {
+			return suite, nil
+		}
+	}
+
+	return nil, fmt.Errorf("the report has no suite with ID=%s", id)
+}
+
+func parsePolicy(name string) (report.MergePolicy, error) {
+	switch name {
+	case "fail":
+		return report.MergePolicyFail, nil
+	case "rename":
+		return report.MergePolicyRename, nil
+	case "merge":
+		return report.MergePolicyMerge, nil
+	}
+
+	return 0, fmt.Errorf("unknown merge policy %q", name)
+}
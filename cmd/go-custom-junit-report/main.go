@@ -0,0 +1,79 @@
+// Command go-custom-junit-report builds a JUnit XML report from the shell. The
+// report being built is kept in a state file, which is itself a JUnit report,
+// so it can be inspected or rendered at any time.
+//
+// Usage:
+//
+//	go-custom-junit-report init [-state file] [-id id] [-name name]
+//	go-custom-junit-report add-suite [-state file] [-id id] [-name name] [-package package] [-hostname]
+//	go-custom-junit-report add-case [-state file] [-suite id] [flags]
+//	go-custom-junit-report merge [-state file] [-policy policy] files...
+//	go-custom-junit-report render [-state file] [-o file] [-format format] [-flatten separator]
+//
+// Run a subcommand with -h to see all of its flags.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// defaultStateFile is the state file used when -state isn't given
+const defaultStateFile = "junit-report.state.xml"
+
+// command is a subcommand. It receives the subcommand arguments and returns an
+// error if it fails.
+type command func(args []string, stdin io.Reader, stdout io.Writer) error
+
+var commands = map[string]command{
+	"init":      runInit,
+	"add-suite": runAddSuite,
+	"add-case":  runAddCase,
+	"merge":     runMerge,
+	"render":    runRender,
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the subcommand in args and returns the exit code
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return 2
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command %q\n", args[0])
+		usage(stderr)
+		return 2
+	}
+
+	err := cmd(args[1:], stdin, stdout)
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", args[0], err)
+		return 1
+	}
+
+	return 0
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: go-custom-junit-report <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+	fmt.Fprintln(w, "  init       create an empty report")
+	fmt.Fprintln(w, "  add-suite  add a test suite to the report")
+	fmt.Fprintln(w, "  add-case   add a test case to a test suite")
+	fmt.Fprintln(w, "  merge      merge other reports into the report")
+	fmt.Fprintln(w, "  render     write the final report as XML, JSON, TAP, Markdown, HTML, SARIF or Code Quality")
+}
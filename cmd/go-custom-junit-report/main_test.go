@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/arquivei/go-custom-junit-report/report"
+	"github.com/stretchr/testify/assert"
+)
+
+func mustRun(t *testing.T, stdin string, args ...string) string {
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	code := run(args, strings.NewReader(stdin), stdout, stderr)
+	assert.Equal(t, 0, code, stderr.String())
+
+	return stdout.String()
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	state := filepath.Join(dir, "state.xml")
+	output := filepath.Join(dir, "report.xml")
+
+	mustRun(t, "", "init", "-state", state, "-id", "r", "-name", "smoke")
+	mustRun(t, "", "add-suite", "-state", state, "-id", "s1", "-name", "suite 1",
+		"-package", "smoke", "-hostname")
+	mustRun(t, "service started", "add-case", "-state", state, "-id", "c1", "-name", "ok",
+		"-time", "1.5s", "-system-out", "-")
+	mustRun(t, "curl: (7) connection refused", "add-case", "-state", state,
+		"-suite", "s1", "-name", "http", "--failure", "request failed",
+		"--failure-content", "-")
+	mustRun(t, "", "add-case", "-state", state, "-name", "db", "-skip", "no database")
+	mustRun(t, "", "render", "-state", state, "-o", output)
+
+	suites, err := report.LoadReport(output)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "smoke", suites.Name)
+	assert.Equal(t, 3, suites.Tests)
+	assert.Equal(t, 1, suites.Failures)
+	assert.Equal(t, 1, suites.Skipped)
+	assert.Equal(t, "1.500", suites.Time.String())
+
+	assert.Equal(t, "smoke", suites.TestSuites[0].Package)
+	assert.NotEmpty(t, suites.TestSuites[0].Hostname)
+	assert.False(t, suites.TestSuites[0].Timestamp.IsZero())
+	assert.Equal(t, suites.TestSuites[0].Timestamp, suites.Timestamp)
+
+	testCases := suites.TestSuites[0].TestCases
+	assert.Equal(t, "service started", testCases[0].SystemOut)
+	assert.Equal(t, "request failed", testCases[1].Failures[0].Message)
+	assert.Equal(t, "curl: (7) connection refused", testCases[1].Failures[0].Content)
+	assert.Equal(t, "no database", testCases[2].Skipped.Message)
+
+	stdout := mustRun(t, "", "render", "-state", state)
+	content, err := suites.MakeReport()
+	assert.Nil(t, err)
+	assert.Equal(t, string(content), stdout)
+}
+
+func TestRun_Merge(t *testing.T) {
+	dir := t.TempDir()
+	state := filepath.Join(dir, "state.xml")
+	shard := filepath.Join(dir, "shard.xml")
+
+	mustRun(t, "", "init", "-state", shard)
+	mustRun(t, "", "add-suite", "-state", shard, "-id", "s")
+	mustRun(t, "", "add-case", "-state", shard, "-name", "a")
+
+	mustRun(t, "", "init", "-state", state)
+	mustRun(t, "", "merge", "-state", state, shard)
+	mustRun(t, "", "merge", "-state", state, "-policy", "rename", shard)
+
+	suites, err := report.LoadReport(state)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(suites.TestSuites))
+	assert.Equal(t, "s-1", suites.TestSuites[1].ID)
+
+	code := run([]string{"merge", "-state", state, shard}, nil, &bytes.Buffer{}, &bytes.Buffer{})
+	assert.Equal(t, 1, code)
+}
+
+func TestRun_Flatten(t *testing.T) {
+	state := filepath.Join(t.TempDir(), "state.xml")
+	nested := filepath.Join("testdata", "nested.xml")
+
+	mustRun(t, "", "init", "-state", state)
+	mustRun(t, "", "merge", "-state", state, nested)
+	stdout := mustRun(t, "", "render", "-state", state, "-flatten", " / ")
+
+	suites, err := report.ParseReport(strings.NewReader(stdout))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(suites.TestSuites))
+	assert.Equal(t, "service / service.endpoint", suites.TestSuites[1].Name)
+	assert.Equal(t, 2, suites.Tests)
+}
+
+func TestRun_Format(t *testing.T) {
+	dir := t.TempDir()
+	state := filepath.Join(dir, "state.json")
+
+	mustRun(t, "", "init", "-state", state, "-name", "report")
+	mustRun(t, "", "add-suite", "-state", state, "-name", "suite")
+	mustRun(t, "", "add-case", "-state", state, "-name", "case")
+
+	stdout := mustRun(t, "", "render", "-state", state, "-format", "tap")
+	assert.Equal(t, "TAP version 13\n1..1\nok 1 - suite / case\n", stdout)
+
+	output := filepath.Join(dir, "report.json")
+	mustRun(t, "", "render", "-state", state, "-o", output)
+	content, err := os.ReadFile(output)
+	assert.Nil(t, err)
+	assert.Contains(t, string(content), `"name": "report"`)
+}
+
+func TestRun_CodeQuality(t *testing.T) {
+	state := filepath.Join(t.TempDir(), "state.xml")
+
+	mustRun(t, "", "init", "-state", state)
+	mustRun(t, "", "add-suite", "-state", state, "-name", "lint")
+	mustRun(t, "", "add-case", "-state", state, "-name", "unused", "-failure", "never read", "-failure-file", "api.proto", "-failure-line", "3")
+	mustRun(t, "", "add-case", "-state", state, "-name", "naming", "-error", "bad name", "-file", "lint_test.go", "-line", "9")
+
+	stdout := mustRun(t, "", "render", "-state", state, "-format", "codequality")
+	assert.Contains(t, stdout, `"check_name": "lint / unused"`)
+	assert.Contains(t, stdout, `"path": "api.proto"`)
+	assert.Contains(t, stdout, `"begin": 3`)
+	assert.Contains(t, stdout, `"path": "lint_test.go"`)
+	assert.Contains(t, stdout, `"severity": "critical"`)
+}
+
+func TestRun_StdinTwice(t *testing.T) {
+	state := filepath.Join(t.TempDir(), "state.xml")
+	mustRun(t, "", "init", "-state", state)
+	mustRun(t, "", "add-suite", "-state", state)
+
+	stderr := &bytes.Buffer{}
+	args := []string{"add-case", "-state", state, "-content", "-", "-system-out", "-"}
+	code := run(args, strings.NewReader("output"), &bytes.Buffer{}, stderr)
+
+	assert.NotEqual(t, 0, code)
+	assert.Contains(t, stderr.String(), "can read from stdin")
+}
+
+func TestRun_Errors(t *testing.T) {
+	dir := t.TempDir()
+	state := filepath.Join(dir, "state.xml")
+	mustRun(t, "", "init", "-state", state)
+
+	tests := [][]string{
+		{},
+		{"unknown"},
+		{"add-suite", "-state", filepath.Join(dir, "missing.xml")},
+		{"add-case", "-state", state, "-name", "no suite"},
+		{"merge", "-state", state, "-policy", "unknown"},
+		{"render", "-state", state, "-unknown"},
+		{"render", "-state", state, "-format", "unknown"},
+	}
+
+	for _, args := range tests {
+		code := run(args, strings.NewReader(""), &bytes.Buffer{}, &bytes.Buffer{})
+		assert.NotEqual(t, 0, code, args)
+	}
+}
@@ -0,0 +1,227 @@
+// Package gotest converts the output of "go test -json" into a report. Each
+// package maps to a test suite and each test, including subtests, maps to a
+// test case.
+package gotest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/arquivei/go-custom-junit-report/report"
+)
+
+// Event is a single event emitted by test2json, which is the format written by
+// "go test -json". See "go doc test2json" for details.
+type Event struct {
+	Time    time.Time `json:"Time"`
+	Action  string    `json:"Action"`
+	Package string    `json:"Package"`
+	Test    string    `json:"Test"`
+	Elapsed float64   `json:"Elapsed"`
+	Output  string    `json:"Output"`
+}
+
+// Converter builds a report from test2json events. Events must be added in the
+// order they were emitted.
+type Converter struct {
+	packages map[string]*packageState
+	order    []string
+}
+
+// packageState holds the state of a package while its events are added
+type packageState struct {
+	name   string
+	tests  map[string]*testState
+	order  []string
+	output strings.Builder
+	action string
+	start  time.Time
+}
+
+// testState holds the state of a test while its events are added
+type testState struct {
+	testCase *report.TestCase
+	output   strings.Builder
+	panic    string
+	done     bool
+}
+
+// NewConverter returns an empty Converter
+func NewConverter() *Converter {
+	return &Converter{
+		packages: map[string]*packageState{},
+	}
+}
+
+// Convert reads "go test -json" output from r and returns the resulting
+// report. Lines that aren't JSON objects, such as build errors when stderr is
+// redirected to stdout, are ignored.
+func Convert(r io.Reader) (*report.TestSuites, error) {
+	converter := NewConverter()
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 || line[0] != '{' {
+			continue
+		}
+
+		var event Event
+		if err := json.Unmarshal(line, &event); err != nil {
+			return nil, fmt.Errorf("cannot convert go test output: %w", err)
+		}
+
+		converter.Add(event)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("cannot convert go test output: %w", err)
+	}
+
+	return converter.TestSuites(), nil
+}
+
+// Add processes an event
+func (c *Converter) Add(event Event) {
+	if len(event.Package) == 0 {
+		return
+	}
+
+	pkg := c.packageState(event.Package)
+	if pkg.start.IsZero() {
+		pkg.start = event.Time
+	}
+
+	if len(event.Test) == 0 {
+		switch event.Action {
+		case "output":
+			pkg.output.WriteString(event.Output)
+		case "pass", "fail", "skip":
+			pkg.action = event.Action
+		}
+		return
+	}
+
+	test := pkg.testState(event.Test)
+	switch event.Action {
+	case "output":
+		test.output.WriteString(event.Output)
+		if len(test.panic) == 0 && strings.HasPrefix(event.Output, "panic: ") {
+			test.panic = strings.TrimSpace(event.Output)
+		}
+	case "pass", "fail", "skip":
+		test.end(event.Action, event.Elapsed)
+	}
+}
+
+// TestSuites returns the report built from the added events. Tests that never
+// ended are reported as errors, and failed packages without any failed test,
+// eg: build failures, are reported as a test case with an error. Packages
+// without tests are omitted.
+func (c *Converter) TestSuites() *report.TestSuites {
+	suites := report.NewAnonymousTestSuites()
+
+	for _, name := range c.order {
+		suite := c.packages[name].testSuite()
+		if suite == nil {
+			continue
+		}
+
+		// Package names are unique, so the suite ID is never duplicated
+		_ = suites.AddTestSuite(suite)
+	}
+
+	return suites
+}
+
+func (c *Converter) packageState(name string) *packageState {
+	pkg, ok := c.packages[name]
+	if !ok {
+		pkg = &packageState{
+			name:  name,
+			tests: map[string]*testState{},
+		}
+		c.packages[name] = pkg
+		c.order = append(c.order, name)
+	}
+
+	return pkg
+}
+
+func (pkg *packageState) testState(name string) *testState {
+	test, ok := pkg.tests[name]
+	if !ok {
+		test = &testState{
+			testCase: report.NewTestCase("", name, pkg.name),
+		}
+		pkg.tests[name] = test
+		pkg.order = append(pkg.order, name)
+	}
+
+	return test
+}
+
+// testSuite returns the test suite of the package or nil if the package has no
+// tests and didn't fail
+func (pkg *packageState) testSuite() *report.TestSuite {
+	suite := report.NewTestSuite(pkg.name, pkg.name)
+	suite.Timestamp = report.NewTimestamp(pkg.start)
+	failed := false
+
+	for _, name := range pkg.order {
+		test := pkg.tests[name]
+		if !test.done {
+			test.testCase.AddError(report.NewError(
+				"test did not finish",
+				"",
+				test.output.String(),
+			))
+		}
+
+		if len(test.testCase.Failures) > 0 || len(test.testCase.Errors) > 0 {
+			failed = true
+		}
+
+		suite.TestCases = append(suite.TestCases, test.testCase)
+	}
+
+	if pkg.action == "fail" && !failed {
+		testCase := report.NewTestCase("", pkg.name, pkg.name)
+		testCase.AddError(report.NewError(
+			"package failed",
+			"",
+			pkg.output.String(),
+		))
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+
+	if len(suite.TestCases) == 0 {
+		return nil
+	}
+
+	return suite
+}
+
+// end records the result of the test
+func (test *testState) end(action string, elapsed float64) {
+	test.done = true
+	test.testCase.Time = report.Duration(elapsed * float64(time.Second))
+	output := test.output.String()
+
+	switch {
+	case action == "skip":
+		test.testCase.SetSkipped(report.NewSkipped("", output))
+	case action == "fail" && len(test.panic) > 0:
+		test.testCase.AddError(report.NewError(test.panic, "panic", output))
+	case action == "fail":
+		test.testCase.AddFailure(report.NewFailure("", "", output))
+	default:
+		test.testCase.SystemOut = output
+	}
+}
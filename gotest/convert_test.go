@@ -0,0 +1,108 @@
+package gotest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arquivei/go-custom-junit-report/report"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvert(t *testing.T) {
+	f, err := os.Open(filepath.Join("testdata", "events.json"))
+	assert.Nil(t, err)
+	defer f.Close()
+
+	suites, err := Convert(f)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1, len(suites.TestSuites))
+	suite := suites.TestSuites[0]
+	assert.Equal(t, "ex/a", suite.ID)
+	assert.Equal(t, "ex/a", suite.Name)
+	assert.Equal(t, "2026-10-18T03:38:22Z", suite.Timestamp.String())
+
+	names := []string{}
+	for _, c := range suite.TestCases {
+		names = append(names, c.Name)
+		assert.Equal(t, "ex/a", c.Classname)
+	}
+	assert.Equal(t, []string{
+		"TestPass",
+		"TestFail",
+		"TestSkip",
+		"TestSub",
+		"TestSub/one",
+		"TestSub/two",
+		"TestPanic",
+	}, names)
+
+	pass := suite.TestCases[0]
+	assert.Equal(t, report.Duration(1500*time.Millisecond), pass.Time)
+	assert.Contains(t, pass.SystemOut, "a_test.go:3: hello")
+	assert.Empty(t, pass.Failures)
+
+	fail := suite.TestCases[1]
+	assert.Equal(t, 1, len(fail.Failures))
+	assert.Contains(t, fail.Failures[0].Content, "a_test.go:4: bad")
+
+	skip := suite.TestCases[2]
+	assert.NotNil(t, skip.Skipped)
+	assert.Contains(t, skip.Skipped.Content, "a_test.go:5: nope")
+
+	assert.Equal(t, 1, len(suite.TestCases[3].Failures))
+	assert.Empty(t, suite.TestCases[4].Failures)
+	assert.Equal(t, 1, len(suite.TestCases[5].Failures))
+
+	panicked := suite.TestCases[6]
+	assert.Empty(t, panicked.Failures)
+	assert.Equal(t, 1, len(panicked.Errors))
+	assert.Equal(t, "panic: boom [recovered, repanicked]", panicked.Errors[0].Message)
+	assert.Equal(t, "panic", panicked.Errors[0].Type)
+	assert.Contains(t, panicked.Errors[0].Content, "goroutine")
+
+	_, err = suites.MakeReport()
+	assert.Nil(t, err)
+	assert.Equal(t, 7, suites.Tests)
+	assert.Equal(t, 1, suites.Skipped)
+}
+
+func TestConvert_PackageFailure(t *testing.T) {
+	input := strings.Join([]string{
+		"# ex/c",
+		`{"Action":"start","Package":"ex/c"}`,
+		`{"Action":"output","Package":"ex/c","Output":"FAIL\tex/c [build failed]\n"}`,
+		`{"Action":"fail","Package":"ex/c","Elapsed":0}`,
+	}, "\n")
+
+	suites, err := Convert(strings.NewReader(input))
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1, len(suites.TestSuites))
+	testCase := suites.TestSuites[0].TestCases[0]
+	assert.Equal(t, "ex/c", testCase.Name)
+	assert.Equal(t, "package failed", testCase.Errors[0].Message)
+	assert.Contains(t, testCase.Errors[0].Content, "build failed")
+}
+
+func TestConvert_UnfinishedTest(t *testing.T) {
+	input := strings.Join([]string{
+		`{"Action":"run","Package":"ex/d","Test":"TestHang"}`,
+		`{"Action":"output","Package":"ex/d","Test":"TestHang","Output":"=== RUN   TestHang\n"}`,
+	}, "\n")
+
+	suites, err := Convert(strings.NewReader(input))
+	assert.Nil(t, err)
+
+	testCase := suites.TestSuites[0].TestCases[0]
+	assert.Equal(t, "test did not finish", testCase.Errors[0].Message)
+}
+
+func TestConvert_Error(t *testing.T) {
+	_, err := Convert(strings.NewReader(`{"Action":`))
+
+	assert.NotNil(t, err)
+}
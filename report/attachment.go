@@ -0,0 +1,142 @@
+package report
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// AttachmentDir is the directory files attached to test cases are copied to,
+// so they can be uploaded with the report and shown next to the test cases by
+// CI tools, such as Jenkins and GitLab. It has the following fields:
+// ReportDir: the directory of the report file.
+// Dir: the directory the files are copied to, relative to ReportDir. If Dir is
+// absolute, the files are copied to it and the absolute paths are written.
+// BaseDir: optional directory the attachment paths written to the report are
+// relative to, usually the project root, since GitLab resolves them from
+// $CI_PROJECT_DIR. The working directory is used if empty. Copies outside of it
+// are written with absolute paths.
+type AttachmentDir struct {
+	ReportDir string
+	Dir       string
+	BaseDir   string
+}
+
+// NewAttachmentDir returns an AttachmentDir that copies files to dir, relative
+// to the directory of the given report file name, eg: files attached with
+// NewAttachmentDir("reports/junit.xml", "attachments") are copied to
+// "reports/attachments".
+func NewAttachmentDir(reportFilename string, dir string) *AttachmentDir {
+	return &AttachmentDir{
+		ReportDir: filepath.Dir(reportFilename),
+		Dir:       dir,
+	}
+}
+
+// Attach copies the file with the given name to dir and appends a
+// [[ATTACHMENT|path]] line with the path of the copy to the test case
+// system-out, which is the convention used by Jenkins and GitLab to link files
+// to test cases. The directory is created if it doesn't exist. If a file with
+// the same name was already attached, a number is added to the name of the
+// copy, eg: "screenshot-1.png".
+func (testCase *TestCase) Attach(dir *AttachmentDir, filename string) error {
+	path, err := dir.copy(filename)
+	if err != nil {
+		return fmt.Errorf("cannot attach %s: %w", filename, err)
+	}
+
+	testCase.mu.Lock()
+	defer testCase.mu.Unlock()
+
+	if len(testCase.SystemOut) > 0 && !strings.HasSuffix(testCase.SystemOut, "\n") {
+		testCase.SystemOut += "\n"
+	}
+	testCase.SystemOut += "[[ATTACHMENT|" + path + "]]\n"
+	return nil
+}
+
+// copy copies the file with the given name to the directory and returns the
+// path of the copy as written to the report
+func (dir *AttachmentDir) copy(filename string) (string, error) {
+	src, err := os.Open(filename)
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	target := dir.Dir
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(dir.ReportDir, target)
+	}
+
+	if err := os.MkdirAll(target, 0755); err != nil {
+		return "", err
+	}
+
+	dst, name, err := createUnique(target, filepath.Base(filename))
+	if err != nil {
+		return "", err
+	}
+
+	copied := filepath.Join(target, name)
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		os.Remove(copied)
+		return "", err
+	}
+
+	if err := dst.Close(); err != nil {
+		os.Remove(copied)
+		return "", err
+	}
+
+	return dir.path(copied)
+}
+
+// path returns the path of the copy with the given file name as written to the
+// report
+func (dir *AttachmentDir) path(copied string) (string, error) {
+	abs, err := filepath.Abs(copied)
+	if err != nil {
+		return "", err
+	}
+
+	if filepath.IsAbs(dir.Dir) {
+		return filepath.ToSlash(abs), nil
+	}
+
+	base := dir.BaseDir
+	if len(base) == 0 {
+		base = "."
+	}
+
+	return filepath.ToSlash(relativeLocation(abs, base)), nil
+}
+
+// createUnique creates a file with the given name in dir, adding a number to
+// the name if it already exists. It returns the file and its name.
+func createUnique(dir string, name string) (*os.File, string, error) {
+	ext := filepath.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+
+	for i := 0; ; i++ {
+		candidate := name
+		if i > 0 {
+			candidate = stem + "-" + strconv.Itoa(i) + ext
+		}
+
+		f, err := os.OpenFile(filepath.Join(dir, candidate), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if errors.Is(err, os.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return nil, "", err
+		}
+
+		return f, candidate, nil
+	}
+}
@@ -0,0 +1,112 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAttachmentDir(t *testing.T) {
+	expected := &AttachmentDir{
+		ReportDir: filepath.Join("reports", "junit"),
+		Dir:       "attachments",
+	}
+
+	assert.Equal(t, expected, NewAttachmentDir(filepath.Join("reports", "junit", "report.xml"), "attachments"))
+}
+
+func TestAttach(t *testing.T) {
+	tmp := t.TempDir()
+	screenshot := filepath.Join(tmp, "screenshot.png")
+	assert.Nil(t, os.WriteFile(screenshot, []byte("png"), 0644))
+
+	reportDir := filepath.Join(tmp, "reports")
+	dir := NewAttachmentDir(filepath.Join(reportDir, "junit.xml"), "attachments")
+	dir.BaseDir = tmp
+
+	testCase := NewTestCase("", "login", "browser")
+	testCase.SystemOut = "opening login page"
+	assert.Nil(t, testCase.Attach(dir, screenshot))
+	assert.Nil(t, testCase.Attach(dir, screenshot))
+
+	assert.Equal(
+		t,
+		"opening login page\n"+
+			"[[ATTACHMENT|reports/attachments/screenshot.png]]\n"+
+			"[[ATTACHMENT|reports/attachments/screenshot-1.png]]\n",
+		testCase.SystemOut,
+	)
+
+	for _, name := range []string{"screenshot.png", "screenshot-1.png"} {
+		content, err := os.ReadFile(filepath.Join(reportDir, "attachments", name))
+		assert.Nil(t, err)
+		assert.Equal(t, "png", string(content))
+	}
+}
+
+func TestAttach_WorkingDir(t *testing.T) {
+	wd, err := os.Getwd()
+	assert.Nil(t, err)
+	defer func() { assert.Nil(t, os.Chdir(wd)) }()
+
+	tmp := t.TempDir()
+	assert.Nil(t, os.Chdir(tmp))
+	assert.Nil(t, os.WriteFile("screenshot.png", []byte("png"), 0644))
+
+	testCase := NewAnonymousTestCase()
+	assert.Nil(t, testCase.Attach(NewAttachmentDir(filepath.Join("reports", "junit.xml"), "attachments"), "screenshot.png"))
+
+	assert.Equal(t, "[[ATTACHMENT|reports/attachments/screenshot.png]]\n", testCase.SystemOut)
+}
+
+func TestAttach_OutsideBaseDir(t *testing.T) {
+	tmp := t.TempDir()
+	screenshot := filepath.Join(tmp, "screenshot.png")
+	assert.Nil(t, os.WriteFile(screenshot, []byte("png"), 0644))
+
+	dir := NewAttachmentDir(filepath.Join(tmp, "reports", "junit.xml"), "attachments")
+	dir.BaseDir = filepath.Join(tmp, "project")
+
+	testCase := NewAnonymousTestCase()
+	assert.Nil(t, testCase.Attach(dir, screenshot))
+
+	expected := filepath.ToSlash(filepath.Join(tmp, "reports", "attachments", "screenshot.png"))
+	assert.Equal(t, "[[ATTACHMENT|"+expected+"]]\n", testCase.SystemOut)
+}
+
+func TestAttach_AbsoluteDir(t *testing.T) {
+	tmp := t.TempDir()
+	har := filepath.Join(tmp, "session.har")
+	assert.Nil(t, os.WriteFile(har, []byte("{}"), 0644))
+
+	target := filepath.Join(tmp, "artifacts")
+	testCase := NewAnonymousTestCase()
+	assert.Nil(t, testCase.Attach(NewAttachmentDir("junit.xml", target), har))
+
+	assert.Equal(t, "[[ATTACHMENT|"+filepath.ToSlash(filepath.Join(target, "session.har"))+"]]\n", testCase.SystemOut)
+	assert.FileExists(t, filepath.Join(target, "session.har"))
+}
+
+func TestAttach_Error(t *testing.T) {
+	tmp := t.TempDir()
+	testCase := NewAnonymousTestCase()
+
+	err := testCase.Attach(NewAttachmentDir(filepath.Join(tmp, "junit.xml"), "attachments"), filepath.Join(tmp, "missing.png"))
+	assert.NotNil(t, err)
+	assert.Empty(t, testCase.SystemOut)
+}
+
+func TestAttach_CopyError(t *testing.T) {
+	tmp := t.TempDir()
+	testCase := NewAnonymousTestCase()
+
+	// reading a directory fails after the copy is created
+	err := testCase.Attach(NewAttachmentDir(filepath.Join(tmp, "junit.xml"), "attachments"), tmp)
+	assert.NotNil(t, err)
+
+	entries, err := os.ReadDir(filepath.Join(tmp, "attachments"))
+	assert.Nil(t, err)
+	assert.Empty(t, entries)
+}
@@ -0,0 +1,82 @@
+package report
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// CodeQualityFormatter renders the failures and errors of the test cases as a
+// GitLab Code Quality report, so they are shown inline in merge request diffs.
+// Each failure and error with a file, its own or the one of its test case, is
+// an issue, whose check name is the name of its suite and test case, with
+// nested suites flattened and their names joined by " / ". Failures are major
+// issues and errors are critical ones. Failures and errors without a file are
+// left out, since Code Quality issues must have a location.
+type CodeQualityFormatter struct{}
+
+// Format implements Formatter
+func (CodeQualityFormatter) Format(suites *TestSuites) ([]byte, error) {
+	summary := suites.Snapshot().summary()
+
+	issues := []*codeQualityIssue{}
+	fingerprints := map[string]int{}
+	for _, finding := range summary.findings() {
+		if len(finding.file) == 0 {
+			continue
+		}
+
+		severity := "major"
+		if finding.kind == "error" {
+			severity = "critical"
+		}
+
+		line := finding.line
+		if line < 1 {
+			line = 1
+		}
+
+		path := filepath.ToSlash(finding.file)
+		key := strings.Join([]string{finding.check, path, strconv.Itoa(line), finding.message}, "\x00")
+		fingerprints[key]++
+		sum := sha256.Sum256([]byte(key + "\x00" + strconv.Itoa(fingerprints[key])))
+
+		issues = append(issues, &codeQualityIssue{
+			Description: finding.message,
+			CheckName:   finding.check,
+			Fingerprint: hex.EncodeToString(sum[:]),
+			Severity:    severity,
+			Location: codeQualityLocation{
+				Path:  path,
+				Lines: codeQualityLines{Begin: line},
+			},
+		})
+	}
+
+	content, err := json.MarshalIndent(issues, "", "  ")
+	if err != nil {
+		return []byte{}, err
+	}
+
+	return append(content, '\n'), nil
+}
+
+type codeQualityIssue struct {
+	Description string              `json:"description"`
+	CheckName   string              `json:"check_name"`
+	Fingerprint string              `json:"fingerprint"`
+	Severity    string              `json:"severity"`
+	Location    codeQualityLocation `json:"location"`
+}
+
+type codeQualityLocation struct {
+	Path  string           `json:"path"`
+	Lines codeQualityLines `json:"lines"`
+}
+
+type codeQualityLines struct {
+	Begin int `json:"begin"`
+}
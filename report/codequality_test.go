@@ -0,0 +1,67 @@
+package report
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCodeQualityFormatter(t *testing.T) {
+	suites, err := LoadReport(filepath.Join("testdata", "findings.xml"))
+	assert.Nil(t, err)
+	duplicated := NewFailure("field id is never read", "unused", "")
+	duplicated.SetLocation("api/user.proto", 12)
+	suites.TestSuites[0].TestCases[0].AddFailure(duplicated)
+	whole := NewFailure("file is empty", "", "")
+	whole.SetLocation("api/empty.proto", 0)
+	suites.TestSuites[0].TestCases[1].AddFailure(whole)
+
+	content, err := CodeQuality.Format(suites)
+	assert.Nil(t, err)
+
+	issues := []*codeQualityIssue{}
+	assert.Nil(t, json.Unmarshal(content, &issues))
+	assert.Equal(t, 3, len(issues))
+
+	assert.Equal(t, "field id is never read", issues[0].Description)
+	assert.Equal(t, "contracts / unused field", issues[0].CheckName)
+	assert.Equal(t, "major", issues[0].Severity)
+	assert.Equal(t, "api/user.proto", issues[0].Location.Path)
+	assert.Equal(t, 12, issues[0].Location.Lines.Begin)
+	assert.Equal(t, 64, len(issues[0].Fingerprint))
+
+	assert.NotEqual(t, issues[0].Fingerprint, issues[1].Fingerprint)
+
+	assert.Equal(t, "contracts / naming", issues[2].CheckName)
+	assert.Equal(t, "api/empty.proto", issues[2].Location.Path)
+	assert.Equal(t, 1, issues[2].Location.Lines.Begin)
+
+	again, err := CodeQuality.Format(suites)
+	assert.Nil(t, err)
+	assert.Equal(t, string(content), string(again))
+}
+
+func TestCodeQualityFormatter_Empty(t *testing.T) {
+	content, err := CodeQuality.Format(NewAnonymousTestSuites())
+	assert.Nil(t, err)
+	assert.Equal(t, "[]\n", string(content))
+}
+
+func TestCodeQualityFormatter_TestCaseLocation(t *testing.T) {
+	suites, err := LoadReport(filepath.Join("testdata", "findings.xml"))
+	assert.Nil(t, err)
+	suites.TestSuites[0].TestCases[1].SetLocation("contracts_test.go", 30)
+
+	content, err := CodeQuality.Format(suites)
+	assert.Nil(t, err)
+
+	issues := []*codeQualityIssue{}
+	assert.Nil(t, json.Unmarshal(content, &issues))
+	assert.Equal(t, 3, len(issues))
+	assert.Equal(t, "contracts_test.go", issues[1].Location.Path)
+	assert.Equal(t, 30, issues[1].Location.Lines.Begin)
+	assert.Equal(t, "critical", issues[2].Severity)
+	assert.Equal(t, "test case errored", issues[2].Description)
+}
@@ -0,0 +1,109 @@
+package report
+
+// CountMode defines how the failures and errors attributes are calculated
+type CountMode int
+
+const (
+	// CountTestCases counts the test cases with at least one failure or error,
+	// as expected by the JUnit format. A test case with both failures and
+	// errors is counted in both attributes.
+	CountTestCases CountMode = iota
+	// CountElements counts every failure and error tag, so a test case with
+	// three failures adds three to the failures attribute.
+	CountElements
+)
+
+// FailureElements returns the total amount of failure tags in the suite and
+// its nested suites, regardless of the count mode
+func (suite *TestSuite) FailureElements() int {
+	defer suite.rlockAll()()
+
+	return suite.failureElements()
+}
+
+// ErrorElements returns the total amount of error tags in the suite and its
+// nested suites, regardless of the count mode
+func (suite *TestSuite) ErrorElements() int {
+	defer suite.rlockAll()()
+
+	return suite.errorElements()
+}
+
+// FailureElements returns the total amount of failure tags in the report,
+// regardless of the count mode
+func (suites *TestSuites) FailureElements() int {
+	defer suites.rlockAll()()
+
+	total := 0
+	for _, suite := range suites.TestSuites {
+		total += suite.failureElements()
+	}
+
+	return total
+}
+
+// ErrorElements returns the total amount of error tags in the report,
+// regardless of the count mode
+func (suites *TestSuites) ErrorElements() int {
+	defer suites.rlockAll()()
+
+	total := 0
+	for _, suite := range suites.TestSuites {
+		total += suite.errorElements()
+	}
+
+	return total
+}
+
+func (suite *TestSuite) failureElements() int {
+	total := 0
+	for _, testCase := range suite.TestCases {
+		total += len(testCase.Failures)
+	}
+
+	for _, nested := range suite.TestSuites {
+		total += nested.failureElements()
+	}
+
+	return total
+}
+
+func (suite *TestSuite) errorElements() int {
+	total := 0
+	for _, testCase := range suite.TestCases {
+		total += len(testCase.Errors)
+	}
+
+	for _, nested := range suite.TestSuites {
+		total += nested.errorElements()
+	}
+
+	return total
+}
+
+// countFailures returns the amount the test case adds to the failures
+// attribute
+func (mode CountMode) countFailures(testCase *TestCase) int {
+	if mode == CountElements {
+		return len(testCase.Failures)
+	}
+
+	if len(testCase.Failures) > 0 {
+		return 1
+	}
+
+	return 0
+}
+
+// countErrors returns the amount the test case adds to the errors attribute
+func (mode CountMode) countErrors(testCase *TestCase) int {
+	if mode == CountElements {
+		return len(testCase.Errors)
+	}
+
+	if len(testCase.Errors) > 0 {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,53 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountMode_TestCases(t *testing.T) {
+	suites := newTestSuites(t, "", "", "")
+	suites.CountMode = CountTestCases
+	testCase := suites.TestSuites[0].TestCases[0]
+	testCase.AddFailure(NewAnonymousFailure("1"))
+	testCase.AddFailure(NewAnonymousFailure("2"))
+	testCase.AddError(NewAnonymousError("3"))
+	suites.resolve()
+
+	assert.Equal(t, 2, suites.Tests)
+	assert.Equal(t, 1, suites.Failures)
+	assert.Equal(t, 1, suites.Errors)
+	assert.Equal(t, 1, suites.TestSuites[0].Failures)
+	assert.Equal(t, 1, suites.TestSuites[0].Errors)
+}
+
+func TestCountMode_Elements(t *testing.T) {
+	suites := newTestSuites(t, "", "", "")
+	suites.CountMode = CountElements
+	testCase := suites.TestSuites[0].TestCases[0]
+	testCase.AddFailure(NewAnonymousFailure("1"))
+	testCase.AddFailure(NewAnonymousFailure("2"))
+	testCase.AddError(NewAnonymousError("3"))
+	suites.resolve()
+
+	assert.Equal(t, 2, suites.Tests)
+	assert.Equal(t, 2, suites.Failures)
+	assert.Equal(t, 1, suites.Errors)
+	assert.Equal(t, 2, suites.TestSuites[0].Failures)
+	assert.Equal(t, 1, suites.TestSuites[0].Errors)
+}
+
+func TestFailureElements(t *testing.T) {
+	suites := newTestSuites(t, "", "", "")
+	suites.CountMode = CountTestCases
+	testCase := suites.TestSuites[0].TestCases[0]
+	testCase.AddFailure(NewAnonymousFailure("1"))
+	testCase.AddFailure(NewAnonymousFailure("2"))
+	testCase.AddError(NewAnonymousError("3"))
+
+	assert.Equal(t, 2, suites.FailureElements())
+	assert.Equal(t, 1, suites.ErrorElements())
+	assert.Equal(t, 2, suites.TestSuites[0].FailureElements())
+	assert.Equal(t, 1, suites.TestSuites[0].ErrorElements())
+}
@@ -0,0 +1,77 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultDurationPrecision is the number of decimal places of the time
+// attributes of reports that don't set TestSuites.Precision
+const DefaultDurationPrecision = 3
+
+// Duration is a time.Duration that maps to a time attribute. It is written and
+// read as fractional seconds, as expected by JUnit consumers, eg: time="1.500".
+type Duration time.Duration
+
+// Seconds returns the duration as a floating point number of seconds
+func (d Duration) Seconds() float64 {
+	return time.Duration(d).Seconds()
+}
+
+// String returns the duration formatted as fractional seconds with
+// DefaultDurationPrecision decimal places
+func (d Duration) String() string {
+	return d.Format(DefaultDurationPrecision)
+}
+
+// Format returns the duration formatted as fractional seconds with the given
+// number of decimal places. Values lower than 1 are treated as 1 so that the
+// value always has a decimal point.
+func (d Duration) Format(precision int) string {
+	if precision < 1 {
+		precision = 1
+	}
+
+	return strconv.FormatFloat(d.Seconds(), 'f', precision, 64)
+}
+
+// MarshalXMLAttr implements xml.MarshalerAttr
+func (d Duration) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return xml.Attr{Name: name, Value: d.String()}, nil
+}
+
+// MarshalJSON implements json.Marshaler. The duration is written as a number
+// of seconds formatted like String, eg: 1.500.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalXMLAttr implements xml.UnmarshalerAttr
+func (d *Duration) UnmarshalXMLAttr(attr xml.Attr) error {
+	parsed, err := ParseDuration(attr.Value)
+	if err != nil {
+		return err
+	}
+
+	*d = parsed
+	return nil
+}
+
+// ParseDuration parses a time attribute value as seconds, eg: "1.5" or "5". An
+// empty value is parsed as 0.
+func ParseDuration(s string) (Duration, error) {
+	s = strings.TrimSpace(s)
+	if len(s) == 0 {
+		return 0, nil
+	}
+
+	seconds, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("cannot parse duration %q: %w", s, err)
+	}
+
+	return Duration(seconds * float64(time.Second)), nil
+}
@@ -0,0 +1,62 @@
+package report
+
+import (
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDurationString(t *testing.T) {
+	d := Duration(1500 * time.Millisecond)
+
+	assert.Equal(t, "1.500", d.String())
+}
+
+func TestDurationFormat(t *testing.T) {
+	d := Duration(1234567 * time.Microsecond)
+
+	assert.Equal(t, "1.234567", d.Format(6))
+	assert.Equal(t, "1.2", d.Format(0))
+}
+
+func TestDurationMarshalXMLAttr(t *testing.T) {
+	actual, err := xml.Marshal(&TestCase{Time: Duration(1500 * time.Millisecond)})
+
+	assert.Nil(t, err)
+	assert.Equal(t, `<TestCase time="1.500"></TestCase>`, string(actual))
+}
+
+func TestDurationUnmarshalXMLAttr(t *testing.T) {
+	actual := &TestCase{}
+	err := xml.Unmarshal([]byte(`<testcase time="1.500"></testcase>`), actual)
+
+	assert.Nil(t, err)
+	assert.Equal(t, Duration(1500*time.Millisecond), actual.Time)
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := map[string]Duration{
+		"":           0,
+		"0":          0,
+		"1.5":        Duration(1500 * time.Millisecond),
+		"0.001":      Duration(time.Millisecond),
+		"1e-3":       Duration(time.Millisecond),
+		"5":          Duration(5 * time.Second),
+		"1500000000": Duration(1500000000 * time.Second),
+	}
+
+	for value, expected := range tests {
+		actual, err := ParseDuration(value)
+
+		assert.Nil(t, err, value)
+		assert.Equal(t, expected, actual, value)
+	}
+}
+
+func TestParseDuration_Error(t *testing.T) {
+	_, err := ParseDuration("1.5s")
+
+	assert.NotNil(t, err)
+}
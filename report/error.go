@@ -1,16 +1,23 @@
 package report
 
+import "encoding/xml"
+
 // Error corresponds to an error tag inside testcase and should be added every
 // time an error happens during testing. A test case can have several errors.
-// It has three fields: Message, Type, and Content. Message maps to the message
-// optional attribute, which can take the error message. Type maps to the type
-// optional attribute, which can take the error type, and Content maps to the
-// tag's content text, which can be a detailed representation of the error, eg:
-// message and stack trace.
+// It has five fields: Message, Type, File, Line, and Content. Message maps to
+// the message optional attribute, which can take the error message. Type maps
+// to the type optional attribute, which can take the error type. File and Line
+// map to the file and line optional attributes, which can take the source
+// location the error happened at. They are omitted if empty. Content maps to
+// the tag's content text, which can be a detailed representation of the error,
+// eg: message and stack trace.
 type Error struct {
-	Message string `xml:"message,attr,omitempty"`
-	Type    string `xml:"type,attr,omitempty"`
-	Content string `xml:",chardata"`
+	Message string `xml:"message,attr,omitempty" json:"message,omitempty"`
+	Type    string `xml:"type,attr,omitempty" json:"type,omitempty"`
+	File    string `xml:"file,attr,omitempty" json:"file,omitempty"`
+	Line    int    `xml:"line,attr,omitempty" json:"line,omitempty"`
+	Content string `xml:",chardata" json:"content,omitempty"`
+	cdata   bool
 }
 
 // NewError returns an Error with the given message, type, and content
@@ -29,3 +36,27 @@ func NewAnonymousError(content string) *Error {
 		Content: content,
 	}
 }
+
+// SetLocation sets the source file and line the error happened at. A line of 0
+// means the whole file.
+func (e *Error) SetLocation(file string, line int) {
+	e.File = file
+	e.Line = line
+}
+
+// MarshalXML implements xml.Marshaler. The content is written as a CDATA
+// section when the error was copied by a Sanitizer that requires it.
+func (e *Error) MarshalXML(encoder *xml.Encoder, start xml.StartElement) error {
+	if e.cdata {
+		return encodeCDATA(encoder, start, cdataElement{
+			Message: e.Message,
+			Type:    e.Type,
+			File:    e.File,
+			Line:    e.Line,
+			Content: e.Content,
+		})
+	}
+
+	type plain Error
+	return encoder.EncodeElement((*plain)(e), start)
+}
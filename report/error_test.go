@@ -1,6 +1,7 @@
 package report
 
 import (
+	"encoding/xml"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -27,3 +28,30 @@ func TestNewAnonymousError(t *testing.T) {
 
 	assert.Equal(t, expected, actual)
 }
+
+func TestErrorMarshalXML(t *testing.T) {
+	e := NewError("msg", "type", "a < b")
+
+	actual, err := xml.Marshal(e)
+	assert.Nil(t, err)
+	assert.Equal(t, `<Error message="msg" type="type">a &lt; b</Error>`, string(actual))
+
+	e.cdata = true
+	actual, err = xml.Marshal(e)
+	assert.Nil(t, err)
+	assert.Equal(t, `<Error message="msg" type="type"><![CDATA[a < b]]></Error>`, string(actual))
+}
+
+func TestErrorSetLocation(t *testing.T) {
+	e := NewError("msg", "type", "content")
+	e.SetLocation("db.go", 7)
+
+	actual, err := xml.Marshal(e)
+	assert.Nil(t, err)
+	assert.Equal(t, `<Error message="msg" type="type" file="db.go" line="7">content</Error>`, string(actual))
+
+	e.cdata = true
+	actual, err = xml.Marshal(e)
+	assert.Nil(t, err)
+	assert.Equal(t, `<Error message="msg" type="type" file="db.go" line="7"><![CDATA[content]]></Error>`, string(actual))
+}
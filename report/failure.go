@@ -1,17 +1,24 @@
 package report
 
+import "encoding/xml"
+
 // Failure corresponds to a failure tag inside testcase and should be added
 // every time a failure happens during testing. A test case can have several
 // failures.
-// It has three fields: Message, Type, and Content. Message maps to the message
-// optional attribute, which can take the failure message. Type maps to the type
-// optional attribute, which can take the failure type, and Content maps to the
-// tag's content text, which can be a detailed representation of the failure,
-// eg: message, class, and file.
+// It has five fields: Message, Type, File, Line, and Content. Message maps to
+// the message optional attribute, which can take the failure message. Type maps
+// to the type optional attribute, which can take the failure type. File and
+// Line map to the file and line optional attributes, which can take the source
+// location the failure refers to, eg: the line a lint check complains about.
+// They are omitted if empty. Content maps to the tag's content text, which can
+// be a detailed representation of the failure, eg: message, class, and file.
 type Failure struct {
-	Message string `xml:"message,attr,omitempty"`
-	Type    string `xml:"type,attr,omitempty"`
-	Content string `xml:",chardata"`
+	Message string `xml:"message,attr,omitempty" json:"message,omitempty"`
+	Type    string `xml:"type,attr,omitempty" json:"type,omitempty"`
+	File    string `xml:"file,attr,omitempty" json:"file,omitempty"`
+	Line    int    `xml:"line,attr,omitempty" json:"line,omitempty"`
+	Content string `xml:",chardata" json:"content,omitempty"`
+	cdata   bool
 }
 
 // NewFailure returns a Failure with the given message, type, and content
@@ -23,10 +30,45 @@ func NewFailure(msg string, failureType string, content string) *Failure {
 	}
 }
 
-// NewAnonymousError returns a Failure with the given content, but no message
+// NewAnonymousFailure returns a Failure with the given content, but no message
 // and type
 func NewAnonymousFailure(content string) *Failure {
 	return &Failure{
 		Content: content,
 	}
 }
+
+// SetLocation sets the source file and line the failure refers to. A line of 0
+// means the whole file.
+func (f *Failure) SetLocation(file string, line int) {
+	f.File = file
+	f.Line = line
+}
+
+// Error returns the failure message, or the content if there is no message. It
+// allows a Failure to be returned as an error from the function given to
+// TestSuite.Run.
+func (f *Failure) Error() string {
+	if len(f.Message) > 0 {
+		return f.Message
+	}
+
+	return f.Content
+}
+
+// MarshalXML implements xml.Marshaler. The content is written as a CDATA
+// section when the failure was copied by a Sanitizer that requires it.
+func (f *Failure) MarshalXML(encoder *xml.Encoder, start xml.StartElement) error {
+	if f.cdata {
+		return encodeCDATA(encoder, start, cdataElement{
+			Message: f.Message,
+			Type:    f.Type,
+			File:    f.File,
+			Line:    f.Line,
+			Content: f.Content,
+		})
+	}
+
+	type plain Failure
+	return encoder.EncodeElement((*plain)(f), start)
+}
@@ -1,6 +1,7 @@
 package report
 
 import (
+	"encoding/xml"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -18,6 +19,11 @@ func TestNewFailure(t *testing.T) {
 	assert.Equal(t, expected, actual)
 }
 
+func TestFailureError(t *testing.T) {
+	assert.Equal(t, "msg", NewFailure("msg", "type", "content").Error())
+	assert.Equal(t, "content", NewAnonymousFailure("content").Error())
+}
+
 func TestNewAnonymousFailure(t *testing.T) {
 	actual := NewAnonymousFailure("content")
 
@@ -27,3 +33,35 @@ func TestNewAnonymousFailure(t *testing.T) {
 
 	assert.Equal(t, expected, actual)
 }
+
+func TestFailureMarshalXML(t *testing.T) {
+	f := NewFailure("msg", "type", "a < b")
+
+	actual, err := xml.Marshal(f)
+	assert.Nil(t, err)
+	assert.Equal(t, `<Failure message="msg" type="type">a &lt; b</Failure>`, string(actual))
+
+	f.cdata = true
+	actual, err = xml.Marshal(f)
+	assert.Nil(t, err)
+	assert.Equal(t, `<Failure message="msg" type="type"><![CDATA[a < b]]></Failure>`, string(actual))
+}
+
+func TestFailureSetLocation(t *testing.T) {
+	f := NewFailure("msg", "type", "content")
+	f.SetLocation("api/user.proto", 12)
+
+	actual, err := xml.Marshal(f)
+	assert.Nil(t, err)
+	assert.Equal(t, `<Failure message="msg" type="type" file="api/user.proto" line="12">content</Failure>`, string(actual))
+
+	f.cdata = true
+	actual, err = xml.Marshal(f)
+	assert.Nil(t, err)
+	assert.Equal(t, `<Failure message="msg" type="type" file="api/user.proto" line="12"><![CDATA[content]]></Failure>`, string(actual))
+
+	parsed := &Failure{}
+	assert.Nil(t, xml.Unmarshal(actual, parsed))
+	assert.Equal(t, "api/user.proto", parsed.File)
+	assert.Equal(t, 12, parsed.Line)
+}
@@ -0,0 +1,83 @@
+package report
+
+import "strings"
+
+// finding is a failure or error of a test case as exported to code analysis
+// formats, such as SARIF and GitLab Code Quality
+type finding struct {
+	check       string
+	kind        string
+	message     string
+	problemType string
+	file        string
+	line        int
+}
+
+// findings returns the failures and errors of all test cases of the summary,
+// in the order they appear in the report. Failures and errors without a file
+// are located at their test case. The read lock must be held.
+func (suites *TestSuites) findings() []*finding {
+	findings := []*finding{}
+	for _, suite := range suites.TestSuites {
+		for _, testCase := range suite.TestCases {
+			check := displayName(suite.Name, suite.ID) + summarySeparator + displayName(testCase.Name, testCase.ID)
+
+			for _, f := range testCase.Failures {
+				findings = append(findings, newFinding(
+					testCase,
+					check,
+					"failure",
+					findingMessage(f.Message, f.Content, "test case failed"),
+					f.Type,
+					f.File,
+					f.Line,
+				))
+			}
+
+			for _, e := range testCase.Errors {
+				findings = append(findings, newFinding(
+					testCase,
+					check,
+					"error",
+					findingMessage(e.Message, e.Content, "test case errored"),
+					e.Type,
+					e.File,
+					e.Line,
+				))
+			}
+		}
+	}
+
+	return findings
+}
+
+// newFinding returns a finding of the test case. If file is empty, the
+// location of the test case is used.
+func newFinding(testCase *TestCase, check string, kind string, message string, problemType string, file string, line int) *finding {
+	if len(file) == 0 {
+		file, line = testCase.File, testCase.Line
+	}
+
+	return &finding{
+		check:       check,
+		kind:        kind,
+		message:     message,
+		problemType: problemType,
+		file:        file,
+		line:        line,
+	}
+}
+
+// findingMessage returns message, or content if there is no message, or
+// fallback if both are empty
+func findingMessage(message string, content string, fallback string) string {
+	if len(message) > 0 {
+		return message
+	}
+
+	if content = strings.TrimSpace(content); len(content) > 0 {
+		return content
+	}
+
+	return fallback
+}
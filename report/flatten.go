@@ -0,0 +1,85 @@
+package report
+
+import "strings"
+
+// Flatten returns a copy of the report without nested suites, for consumers
+// that don't support them. Every nested suite is moved to the top level, right
+// after its parent, with its name and ID joined to the ones of its parents
+// with separator, eg: "api / users / create". Suites without an ID are left
+// without one. Nested suites inherit the package and hostname of their parents
+// if they don't have their own. A parent is only kept if it has test cases,
+// properties, or output of its own, and its WallTime is dropped since it
+// includes the time of the nested suites. The copy shares nothing with the
+// original report, and all values of the copy are calculated.
+func (suites *TestSuites) Flatten(separator string) *TestSuites {
+	unlock := suites.rlockAll()
+	copied := suites.copy()
+	unlock()
+
+	return copied.flatten(separator)
+}
+
+// flatten flattens a copy of the report in place and returns it
+func (suites *TestSuites) flatten(separator string) *TestSuites {
+	nested := suites.TestSuites
+	suites.TestSuites = nil
+	for _, suite := range nested {
+		suites.TestSuites = append(suites.TestSuites, flattenTestSuite(suite, nil, separator)...)
+	}
+
+	suites.resolve()
+	return suites
+}
+
+// flattenTestSuite flattens a copy of suite in place and returns it followed by
+// its flattened nested suites. parent is the already flattened parent of suite,
+// if any.
+func flattenTestSuite(suite *TestSuite, parent *TestSuite, separator string) []*TestSuite {
+	nested := suite.TestSuites
+	suite.TestSuites = nil
+
+	if parent != nil {
+		if len(suite.ID) > 0 {
+			suite.ID = joinNonEmpty(separator, parent.ID, suite.ID)
+		}
+		suite.Name = joinNonEmpty(separator, parent.Name, suite.Name)
+		if len(suite.Package) == 0 {
+			suite.Package = parent.Package
+		}
+		if len(suite.Hostname) == 0 {
+			suite.Hostname = parent.Hostname
+		}
+	}
+
+	if len(nested) == 0 {
+		return []*TestSuite{suite}
+	}
+
+	suite.WallTime = 0
+
+	result := []*TestSuite{}
+	if len(suite.TestCases) > 0 ||
+		suite.Properties != nil ||
+		len(suite.SystemOut) > 0 ||
+		len(suite.SystemErr) > 0 {
+		result = append(result, suite)
+	}
+
+	for _, child := range nested {
+		result = append(result, flattenTestSuite(child, suite, separator)...)
+	}
+
+	return result
+}
+
+// joinNonEmpty joins the non-empty elements with separator
+func joinNonEmpty(separator string, elements ...string) string {
+	nonEmpty := []string{}
+	for _, e := range elements {
+		if len(e) > 0 {
+			nonEmpty = append(nonEmpty, e)
+		}
+	}
+
+	return strings.Join(nonEmpty, separator)
+}
@@ -0,0 +1,56 @@
+package report
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlatten(t *testing.T) {
+	suites, err := LoadReport(filepath.Join("testdata", "flatten_nested.xml"))
+	assert.Nil(t, err)
+	suites.TestSuites[0].WallTime = Duration(5 * time.Second)
+
+	actual := suites.Flatten(" / ")
+
+	assert.Equal(t, 3, len(actual.TestSuites))
+
+	api := actual.TestSuites[0]
+	assert.Equal(t, "api", api.ID)
+	assert.Equal(t, "api", api.Name)
+	assert.Nil(t, api.TestSuites)
+	assert.Equal(t, 1, api.Tests)
+	assert.Equal(t, Duration(0), api.WallTime)
+
+	create := actual.TestSuites[1]
+	assert.Equal(t, "api / users / create", create.ID)
+	assert.Equal(t, "api / users / create", create.Name)
+	assert.Equal(t, "api", create.Package)
+	assert.Equal(t, "ci-runner", create.Hostname)
+	assert.Equal(t, 2, create.Tests)
+	assert.Equal(t, 1, create.Failures)
+	assert.Equal(t, suites.TestSuites[0].TestSuites[0].TestSuites[0].TestCases[0], create.TestCases[0])
+	assert.NotSame(t, suites.TestSuites[0].TestSuites[0].TestSuites[0].TestCases[0], create.TestCases[0])
+
+	list := actual.TestSuites[2]
+	assert.Empty(t, list.ID)
+	assert.Equal(t, "api / users / list", list.Name)
+	assert.Equal(t, "other", list.Hostname)
+
+	assert.Equal(t, 4, actual.Tests)
+	assert.Equal(t, 1, actual.Failures)
+	assert.Equal(t, Duration(time.Second), actual.Time)
+	assert.Nil(t, actual.Validate())
+
+	// The original report is left untouched
+	assert.Equal(t, 1, len(suites.TestSuites))
+	assert.Equal(t, "create", suites.TestSuites[0].TestSuites[0].TestSuites[0].ID)
+	assert.Equal(t, Duration(5*time.Second), suites.TestSuites[0].WallTime)
+}
+
+func TestJoinNonEmpty(t *testing.T) {
+	assert.Equal(t, "a.c", joinNonEmpty(".", "a", "", "c"))
+	assert.Equal(t, "", joinNonEmpty(".", "", ""))
+}
@@ -0,0 +1,240 @@
+package report
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Formatter renders a report in a given format. Formatters calculate all
+// values of the report and may be used while it is being built from other
+// goroutines, so implementations outside this package should render the
+// report returned by TestSuites.Snapshot.
+type Formatter interface {
+	Format(suites *TestSuites) ([]byte, error)
+}
+
+// Formatters for the supported formats
+var (
+	// CompactXML renders the JUnit XML without indentation
+	CompactXML Formatter = XMLFormatter{}
+	// IndentedXML renders the JUnit XML indented with four spaces, as
+	// MakeReport does
+	IndentedXML Formatter = XMLFormatter{Indent: "    "}
+	// JSON renders the report model as indented JSON
+	JSON Formatter = JSONFormatter{Indent: "  "}
+	// TAP renders the test cases in the Test Anything Protocol version 13
+	TAP Formatter = TAPFormatter{}
+	// Markdown renders the summary of MakeMarkdown
+	Markdown Formatter = MarkdownFormatter{}
+	// HTML renders the summary page of MakeHTML
+	HTML Formatter = HTMLFormatter{}
+	// SARIF renders the failures and errors as a SARIF 2.1.0 document
+	SARIF Formatter = SARIFFormatter{}
+	// CodeQuality renders the failures and errors as a GitLab Code Quality
+	// report
+	CodeQuality Formatter = CodeQualityFormatter{}
+)
+
+// formatterExtensions maps file extensions to the formatter used for them
+var formatterExtensions = map[string]Formatter{
+	".xml":      IndentedXML,
+	".json":     JSON,
+	".tap":      TAP,
+	".md":       Markdown,
+	".markdown": Markdown,
+	".html":     HTML,
+	".htm":      HTML,
+	".sarif":    SARIF,
+}
+
+// FormatterForFile returns the formatter for the extension of the given file
+// name, eg: JSON for "report.json". A ".gz" extension is ignored, so
+// "report.json.gz" also uses JSON. IndentedXML is returned for unknown
+// extensions.
+func FormatterForFile(filename string) Formatter {
+	filename = strings.TrimSuffix(strings.ToLower(filename), ".gz")
+	if formatter, ok := formatterExtensions[filepath.Ext(filename)]; ok {
+		return formatter
+	}
+
+	return IndentedXML
+}
+
+// XMLFormatter renders the JUnit XML. Each element is indented with Indent, or
+// written in a single line if Indent is empty.
+type XMLFormatter struct {
+	Indent string
+}
+
+// Format implements Formatter
+func (f XMLFormatter) Format(suites *TestSuites) ([]byte, error) {
+	rendered := suites.rendered()
+
+	var content []byte
+	var err error
+	if len(f.Indent) > 0 {
+		content, err = xml.MarshalIndent(rendered, "", f.Indent)
+	} else {
+		content, err = xml.Marshal(rendered)
+	}
+
+	if err != nil {
+		return []byte{}, err
+	}
+
+	return []byte(xml.Header + string(content)), nil
+}
+
+// JSONFormatter renders the report model as JSON, with the same elements and
+// names as the JUnit XML. Times are written as numbers of seconds and
+// timestamps as ISO-8601 strings. Each element is indented with Indent, or
+// written in a single line if Indent is empty.
+type JSONFormatter struct {
+	Indent string
+}
+
+// Format implements Formatter
+func (f JSONFormatter) Format(suites *TestSuites) ([]byte, error) {
+	rendered := suites.rendered()
+
+	var content []byte
+	var err error
+	if len(f.Indent) > 0 {
+		content, err = json.MarshalIndent(rendered, "", f.Indent)
+	} else {
+		content, err = json.Marshal(rendered)
+	}
+
+	if err != nil {
+		return []byte{}, err
+	}
+
+	return append(content, '\n'), nil
+}
+
+// TAPFormatter renders the test cases in the Test Anything Protocol version
+// 13. Each test case is a test point named after its suite and its own name,
+// with nested suites flattened and their names joined by " / ". Skipped test
+// cases have a SKIP directive, and the failures and errors of failed test
+// cases are written in a YAML block.
+type TAPFormatter struct{}
+
+// Format implements Formatter
+func (TAPFormatter) Format(suites *TestSuites) ([]byte, error) {
+	summary := suites.Snapshot().summary()
+
+	tap := &strings.Builder{}
+	tap.WriteString("TAP version 13\n")
+	fmt.Fprintf(tap, "1..%d\n", summary.Tests)
+
+	n := 0
+	for _, suite := range summary.TestSuites {
+		for _, testCase := range suite.TestCases {
+			n++
+			writeTAPTestCase(tap, n, suite, testCase)
+		}
+	}
+
+	return []byte(tap.String()), nil
+}
+
+// writeTAPTestCase writes the test point of a test case
+func writeTAPTestCase(tap *strings.Builder, n int, suite *TestSuite, testCase *TestCase) {
+	status := "ok"
+	if !testCase.passed() {
+		status = "not ok"
+	}
+
+	description := displayName(suite.Name, suite.ID) + summarySeparator + displayName(testCase.Name, testCase.ID)
+	fmt.Fprintf(tap, "%s %d - %s", status, n, tapText(description))
+
+	if testCase.Skipped != nil && testCase.passed() {
+		tap.WriteString(" # SKIP")
+		if len(testCase.Skipped.Message) > 0 {
+			tap.WriteString(" " + tapText(testCase.Skipped.Message))
+		}
+	}
+	tap.WriteString("\n")
+
+	if testCase.passed() {
+		return
+	}
+
+	tap.WriteString("  ---\n")
+	fmt.Fprintf(tap, "  duration_ms: %d\n", time.Duration(testCase.Time).Milliseconds())
+	if len(testCase.Failures) > 0 {
+		tap.WriteString("  failures:\n")
+		for _, f := range testCase.Failures {
+			writeTAPProblem(tap, f.Message, f.Type, f.Content)
+		}
+	}
+	if len(testCase.Errors) > 0 {
+		tap.WriteString("  errors:\n")
+		for _, e := range testCase.Errors {
+			writeTAPProblem(tap, e.Message, e.Type, e.Content)
+		}
+	}
+	tap.WriteString("  ...\n")
+}
+
+// writeTAPProblem writes a failure or error as an element of a YAML sequence
+func writeTAPProblem(tap *strings.Builder, message string, problemType string, content string) {
+	prefix := "    - "
+	write := func(key string, value string) {
+		tap.WriteString(prefix + key + ": " + strconv.Quote(value) + "\n")
+		prefix = "      "
+	}
+
+	if len(message) > 0 {
+		write("message", message)
+	}
+	if len(problemType) > 0 {
+		write("type", problemType)
+	}
+
+	content = strings.TrimRight(content, "\n")
+	if len(content) > 0 {
+		tap.WriteString(prefix + "data: |\n")
+		prefix = "      "
+		for _, line := range strings.Split(content, "\n") {
+			tap.WriteString("        " + line + "\n")
+		}
+	}
+
+	if prefix == "    - " {
+		tap.WriteString(prefix + "{}\n")
+	}
+}
+
+// tapText returns s in a single line with the characters that have a meaning
+// in a test point description escaped
+func tapText(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	return tapEscaper.Replace(s)
+}
+
+var tapEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"#", `\#`,
+)
+
+// MarkdownFormatter renders the Markdown summary of the report
+type MarkdownFormatter struct{}
+
+// Format implements Formatter
+func (MarkdownFormatter) Format(suites *TestSuites) ([]byte, error) {
+	return suites.MakeMarkdown()
+}
+
+// HTMLFormatter renders the HTML summary page of the report
+type HTMLFormatter struct{}
+
+// Format implements Formatter
+func (HTMLFormatter) Format(suites *TestSuites) ([]byte, error) {
+	return suites.MakeHTML()
+}
@@ -0,0 +1,141 @@
+package report
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatterForFile(t *testing.T) {
+	assert.Equal(t, IndentedXML, FormatterForFile("report.xml"))
+	assert.Equal(t, JSON, FormatterForFile("report.json"))
+	assert.Equal(t, JSON, FormatterForFile("dir/REPORT.JSON.gz"))
+	assert.Equal(t, TAP, FormatterForFile("report.tap"))
+	assert.Equal(t, Markdown, FormatterForFile("report.md"))
+	assert.Equal(t, HTML, FormatterForFile("report.html"))
+	assert.Equal(t, IndentedXML, FormatterForFile("report"))
+	assert.Equal(t, IndentedXML, FormatterForFile("report.txt"))
+}
+
+func TestXMLFormatter(t *testing.T) {
+	suites := newTestSuites(t, "id", "id")
+
+	indented, err := IndentedXML.Format(suites)
+	assert.Nil(t, err)
+	expected, err := suites.MakeReport()
+	assert.Nil(t, err)
+	assert.Equal(t, string(expected), string(indented))
+
+	compact, err := CompactXML.Format(suites)
+	assert.Nil(t, err)
+	assert.Equal(
+		t,
+		`<?xml version="1.0" encoding="UTF-8"?>`+"\n"+
+			`<testsuites tests="1" failures="0" errors="0" skipped="0">`+
+			`<testsuite id="id" name="name" tests="1" failures="0" errors="0" skipped="0">`+
+			`<testcase id="id" name="name" classname="class"></testcase>`+
+			`</testsuite></testsuites>`,
+		string(compact),
+	)
+}
+
+func TestXMLFormatter_Precision(t *testing.T) {
+	suites := newTestSuites(t, "id", "id")
+	suites.TestSuites[0].TestCases[0].Time = Duration(1234567 * time.Microsecond)
+	suites.Precision = 6
+
+	actual, err := CompactXML.Format(suites)
+	assert.Nil(t, err)
+	assert.Contains(t, string(actual), `<testsuites tests="1" failures="0" errors="0" skipped="0" time="1.234567">`)
+	assert.Contains(t, string(actual), `<testcase id="id" name="name" time="1.234567" classname="class">`)
+
+	assert.Equal(t, "1.235", suites.Time.String())
+}
+
+func TestJSONFormatter_Precision(t *testing.T) {
+	suites := newTestSuites(t, "id", "id")
+	suites.TestSuites[0].TestCases[0].Time = Duration(1234567 * time.Microsecond)
+	suites.Precision = 6
+
+	actual, err := JSONFormatter{}.Format(suites)
+	assert.Nil(t, err)
+	assert.Contains(t, string(actual), `"skipped":0,"time":1.234567,`)
+	assert.Contains(t, string(actual), `"name":"name","time":1.234567,"classname":"class"`)
+
+	assert.Equal(t, "1.235", suites.Time.String())
+}
+
+func TestJSONFormatter(t *testing.T) {
+	suites := NewTestSuites("id", "name")
+	suites.Timestamp = NewTimestamp(time.Date(2021, 3, 4, 15, 4, 5, 0, time.UTC))
+	suites.SetProperty("sha", "abc")
+
+	suite := NewTestSuite("suite", "suite")
+	testCase := NewTestCase("", "case", "class")
+	testCase.Time = Duration(1500 * time.Millisecond)
+	testCase.AddFailure(NewFailure("boom", "assert", "trace"))
+	suite.TestCases = []*TestCase{testCase}
+	suites.TestSuites = []*TestSuite{suite}
+
+	content, err := JSONFormatter{}.Format(suites)
+	assert.Nil(t, err)
+	assert.Equal(
+		t,
+		`{"id":"id","name":"name","timestamp":"2021-03-04T15:04:05Z","tests":1,"failures":1,"errors":0,"skipped":0,"time":1.500,`+
+			`"properties":[{"name":"sha","value":"abc"}],`+
+			`"testsuites":[{"id":"suite","name":"suite","timestamp":null,"time":1.500,"tests":1,"failures":1,"errors":0,"skipped":0,`+
+			`"testcases":[{"name":"case","time":1.500,"classname":"class",`+
+			`"failures":[{"message":"boom","type":"assert","content":"trace"}]}]}]}`+"\n",
+		string(content),
+	)
+
+	indented, err := JSON.Format(suites)
+	assert.Nil(t, err)
+
+	decoded := map[string]interface{}{}
+	assert.Nil(t, json.Unmarshal(indented, &decoded))
+	assert.Equal(t, 1.5, decoded["time"])
+}
+
+func TestJSONFormatter_ZeroTimestamp(t *testing.T) {
+	content, err := JSON.Format(NewAnonymousTestSuites())
+	assert.Nil(t, err)
+	assert.Contains(t, string(content), `"timestamp": null`)
+}
+
+func TestTAPFormatter(t *testing.T) {
+	suites, err := LoadReport(filepath.Join("testdata", "summary.xml"))
+	assert.Nil(t, err)
+
+	content, err := TAP.Format(suites)
+	assert.Nil(t, err)
+
+	expected := "TAP version 13\n" +
+		"1..4\n" +
+		"ok 1 - api / health\n" +
+		"not ok 2 - api / create | user\n" +
+		"  ---\n" +
+		"  duration_ms: 500\n" +
+		"  failures:\n" +
+		"    - message: \"expected *201*\"\n" +
+		"      type: \"assert\"\n" +
+		"      data: |\n" +
+		"        got 500\n" +
+		"        ```\n" +
+		"        body\n" +
+		"        ```\n" +
+		"  errors:\n" +
+		"    - data: |\n" +
+		"        connection reset\n" +
+		"  ...\n" +
+		"ok 3 - api / users / list # SKIP not implemented\n" +
+		"ok 4 - (unnamed) / (unnamed)\n"
+	assert.Equal(t, expected, string(content))
+}
+
+func TestTAPText(t *testing.T) {
+	assert.Equal(t, `a \# b \\ c`, tapText("a #  b \\\n c"))
+}
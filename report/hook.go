@@ -0,0 +1,62 @@
+package report
+
+// Names of the suite hooks, as given to a HookNamer
+const (
+	HookSetup    = "setup"
+	HookTeardown = "teardown"
+)
+
+// HookNamer returns the name and class name of the test case that reports a
+// failed suite hook. hook is either HookSetup or HookTeardown.
+type HookNamer func(suite *TestSuite, hook string) (name string, classname string)
+
+// DefaultHookNamer names the test case after the hook and uses the suite name as
+// the class name, eg: name="setup" classname="database".
+func DefaultHookNamer(suite *TestSuite, hook string) (string, string) {
+	return hook, suite.Name
+}
+
+// Setup runs fn as the setup of the suite, eg: starting a database used by
+// all of its test cases. If fn returns an error or panics, a test case with the
+// error is added to the suite, so the report shows the suite as failed even if
+// no test case ran, and the test case is returned. Otherwise nothing is added
+// and nil is returned. The test case is named by the suite HookCaseNamer.
+func (suite *TestSuite) Setup(fn func() error) *TestCase {
+	return suite.runHook(HookSetup, fn)
+}
+
+// Teardown runs fn as the teardown of the suite. Its errors are recorded the
+// same way as the ones of Setup.
+func (suite *TestSuite) Teardown(fn func() error) *TestCase {
+	return suite.runHook(HookTeardown, fn)
+}
+
+// runHook runs fn and adds a test case to the suite if it fails. Failures are
+// recorded as errors, since a failed hook isn't a failed assertion.
+func (suite *TestSuite) runHook(hook string, fn func() error) *TestCase {
+	namer := suite.HookCaseNamer
+	if namer == nil {
+		namer = DefaultHookNamer
+	}
+	name, classname := namer(suite, hook)
+
+	testCase := NewTestCase("", name, classname)
+	suite.captureLocation(testCase, 2)
+	testCase.run(func(*TestCase) error {
+		return fn()
+	})
+
+	for _, f := range testCase.Failures {
+		e := NewError(f.Message, f.Type, f.Content)
+		e.SetLocation(f.File, f.Line)
+		testCase.Errors = append(testCase.Errors, e)
+	}
+	testCase.Failures = nil
+
+	if len(testCase.Errors) == 0 {
+		return nil
+	}
+
+	suite.addAnonymous(testCase)
+	return testCase
+}
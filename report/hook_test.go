@@ -0,0 +1,73 @@
+package report
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetup_Success(t *testing.T) {
+	suite := NewTestSuite("id", "database")
+
+	actual := suite.Setup(func() error {
+		return nil
+	})
+
+	assert.Nil(t, actual)
+	assert.Empty(t, suite.TestCases)
+}
+
+func TestSetup_Error(t *testing.T) {
+	suite := NewTestSuite("id", "database")
+
+	actual := suite.Setup(func() error {
+		return errors.New("connection refused")
+	})
+
+	assert.Equal(t, []*TestCase{actual}, suite.TestCases)
+	assert.Equal(t, "setup", actual.Name)
+	assert.Equal(t, "database", actual.Classname)
+	assert.Equal(t, 1, len(actual.Errors))
+	assert.Equal(t, "connection refused", actual.Errors[0].Message)
+
+	suites := NewAnonymousTestSuites()
+	assert.Nil(t, suites.AddTestSuite(suite))
+	_, err := suites.MakeReport()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, suites.Tests)
+	assert.Equal(t, 1, suites.Errors)
+}
+
+func TestTeardown_FailureAndPanic(t *testing.T) {
+	suite := NewTestSuite("id", "database")
+
+	failed := suite.Teardown(func() error {
+		return NewFailure("msg", "type", "content")
+	})
+	panicked := suite.Teardown(func() error {
+		panic("boom")
+	})
+
+	assert.Equal(t, []*TestCase{failed, panicked}, suite.TestCases)
+	assert.Equal(t, "teardown", failed.Name)
+	assert.Empty(t, failed.Failures)
+	assert.Equal(t, []*Error{NewError("msg", "type", "content")}, failed.Errors)
+	assert.Equal(t, "boom", panicked.Errors[0].Message)
+	assert.Equal(t, "panic", panicked.Errors[0].Type)
+}
+
+func TestHookCaseNamer(t *testing.T) {
+	suite := NewTestSuite("id", "database")
+	suite.HookCaseNamer = func(suite *TestSuite, hook string) (string, string) {
+		return fmt.Sprintf("%s [%s]", suite.Name, hook), "hooks"
+	}
+
+	actual := suite.Setup(func() error {
+		return errors.New("connection refused")
+	})
+
+	assert.Equal(t, "database [setup]", actual.Name)
+	assert.Equal(t, "hooks", actual.Classname)
+}
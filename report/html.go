@@ -0,0 +1,119 @@
+package report
+
+import (
+	"bytes"
+	"html/template"
+)
+
+// MakeHTML generates a self-contained HTML page summarizing the report. Each
+// suite is a collapsible section, open if it has failed test cases, with the
+// status and time of every test case and the details of its failures and
+// errors. Nested suites are shown flattened, with their names joined by " / ".
+// All values are automatically calculated when calling this method.
+func (suites *TestSuites) MakeHTML() ([]byte, error) {
+	summary := suites.Snapshot().summary()
+
+	page := &htmlReport{
+		TestSuites: summary,
+		Title:      summary.title(),
+		Passed:     summary.passed(),
+	}
+	for _, suite := range summary.TestSuites {
+		view := &htmlSuite{
+			TestSuite: suite,
+			Failed:    suite.Failures+suite.Errors > 0,
+		}
+		for _, testCase := range suite.TestCases {
+			view.TestCases = append(view.TestCases, &htmlTestCase{
+				TestCase: testCase,
+				Status:   testCase.status(),
+			})
+		}
+		page.Suites = append(page.Suites, view)
+	}
+
+	content := &bytes.Buffer{}
+	if err := htmlTemplate.Execute(content, page); err != nil {
+		return []byte{}, err
+	}
+
+	return content.Bytes(), nil
+}
+
+// htmlReport is the data of the HTML template
+type htmlReport struct {
+	*TestSuites
+	Title  string
+	Passed int
+	Suites []*htmlSuite
+}
+
+// htmlSuite is a suite as shown in the HTML template
+type htmlSuite struct {
+	*TestSuite
+	Failed    bool
+	TestCases []*htmlTestCase
+}
+
+// htmlTestCase is a test case as shown in the HTML template
+type htmlTestCase struct {
+	*TestCase
+	Status string
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"duration": durationLabel,
+	"name":     displayName,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #1f2328; }
+table { border-collapse: collapse; margin-bottom: 1em; }
+th, td { padding: 0.3em 0.8em; border-bottom: 1px solid #d0d7de; text-align: left; }
+td.number, th.number { text-align: right; }
+details { border: 1px solid #d0d7de; border-radius: 6px; margin-bottom: 0.5em; padding: 0.5em 1em; }
+summary { cursor: pointer; font-weight: 600; }
+pre { background: #f6f8fa; padding: 0.8em; overflow-x: auto; white-space: pre-wrap; }
+.passed { color: #1a7f37; }
+.failed, .error { color: #cf222e; }
+.skipped { color: #9a6700; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p><strong>{{.Tests}} tests</strong>: {{.Passed}} passed, {{.Failures}} failed, {{.Errors}} errored, {{.Skipped}} skipped in {{duration .Time $.Precision}}</p>
+{{- range .Suites}}
+<details{{if .Failed}} open{{end}}>
+<summary class="{{if .Failed}}failed{{else}}passed{{end}}">{{name .Name .ID}} ({{.Tests}} tests, {{.Failures}} failures, {{.Errors}} errors, {{.Skipped}} skipped, {{duration .Time $.Precision}})</summary>
+<table>
+<tr><th>Test case</th><th>Class name</th><th>Status</th><th class="number">Time</th></tr>
+{{- range .TestCases}}
+<tr><td>{{name .Name .ID}}</td><td>{{.Classname}}</td><td class="{{.Status}}">{{.Status}}</td><td class="number">{{duration .Time $.Precision}}</td></tr>
+{{- end}}
+</table>
+{{- range .TestCases}}
+{{- $name := name .Name .ID}}
+{{- range .Failures}}
+<h3 class="failed">{{$name}}: failure{{if .Message}}: {{.Message}}{{end}}{{if .Type}} ({{.Type}}){{end}}</h3>
+{{- if .Content}}
+<pre>{{.Content}}</pre>
+{{- end}}
+{{- end}}
+{{- range .Errors}}
+<h3 class="error">{{$name}}: error{{if .Message}}: {{.Message}}{{end}}{{if .Type}} ({{.Type}}){{end}}</h3>
+{{- if .Content}}
+<pre>{{.Content}}</pre>
+{{- end}}
+{{- end}}
+{{- if .Skipped}}{{if .Skipped.Message}}
+<p class="skipped">{{$name}}: skipped: {{.Skipped.Message}}</p>
+{{- end}}{{end}}
+{{- end}}
+</details>
+{{- end}}
+</body>
+</html>
+`))
@@ -0,0 +1,46 @@
+package report
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMakeHTML(t *testing.T) {
+	suites, err := LoadReport(filepath.Join("testdata", "summary.xml"))
+	assert.Nil(t, err)
+	suites.TestSuites[0].TestCases[1].Failures[0].Content = "<script>alert(1)</script>"
+
+	actual, err := suites.MakeHTML()
+	assert.Nil(t, err)
+
+	html := string(actual)
+	assert.Contains(t, html, "<title>smoke tests</title>")
+	assert.Contains(t, html, "<strong>4 tests</strong>: 2 passed, 1 failed, 1 errored, 1 skipped in 2.000s")
+	assert.Contains(t, html, `<details open>
+<summary class="failed">api (2 tests, 1 failures, 1 errors, 0 skipped, 2.000s)</summary>`)
+	assert.Contains(t, html, `<details>
+<summary class="passed">api / users (1 tests, 0 failures, 0 errors, 1 skipped, 0.000s)</summary>`)
+	assert.Contains(t, html, `<tr><td>create | user</td><td>api.users</td><td class="failed">failed</td><td class="number">0.500s</td></tr>`)
+	assert.Contains(t, html, `<h3 class="failed">create | user: failure: expected *201* (assert)</h3>`)
+	assert.Contains(t, html, `<pre>&lt;script&gt;alert(1)&lt;/script&gt;</pre>`)
+	assert.Contains(t, html, `<h3 class="error">create | user: error</h3>
+<pre>connection reset</pre>`)
+	assert.Contains(t, html, `<p class="skipped">list: skipped: not implemented</p>`)
+	assert.Contains(t, html, `<summary class="passed">(unnamed) (1 tests`)
+	assert.NotContains(t, html, "<script>")
+}
+
+func TestMakeHTML_Sanitizer(t *testing.T) {
+	suites, err := LoadReport(filepath.Join("testdata", "summary.xml"))
+	assert.Nil(t, err)
+	suites.TestSuites[0].TestCases[1].Failures[0].Content = "\x1b[31mred\x1b[0m"
+	suites.Sanitizer = NewSanitizer(SanitizeStrip)
+	suites.Sanitizer.StripANSI = true
+
+	actual, err := suites.MakeHTML()
+	assert.Nil(t, err)
+
+	assert.Contains(t, string(actual), "<pre>red</pre>")
+}
@@ -0,0 +1,54 @@
+package report
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// CallerLocation returns the file and line of a caller in the stack of the
+// goroutine. The argument skip is the number of stack frames to ascend, with 0
+// identifying the caller of CallerLocation. The file is made relative to root,
+// usually the repository root, since CI tools expect paths relative to it.
+// Files outside of root and all files when root is empty are returned with
+// absolute paths. An empty file and 0 are returned if the location can't be
+// found.
+func CallerLocation(skip int, root string) (string, int) {
+	_, file, line, ok := runtime.Caller(skip + 1)
+	if !ok {
+		return "", 0
+	}
+
+	return relativeLocation(file, root), line
+}
+
+// relativeLocation returns file relative to root, using forward slashes, or
+// file itself if it is outside of root
+func relativeLocation(file string, root string) string {
+	if len(root) == 0 {
+		return file
+	}
+
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return file
+	}
+
+	rel, err := filepath.Rel(root, filepath.FromSlash(file))
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return file
+	}
+
+	return filepath.ToSlash(rel)
+}
+
+// captureLocation records in testCase the location of the caller skip frames
+// above the caller of captureLocation, if the suite CaptureLocation is set and
+// testCase has no file yet
+func (suite *TestSuite) captureLocation(testCase *TestCase, skip int) {
+	if !suite.CaptureLocation || len(testCase.File) > 0 {
+		return
+	}
+
+	testCase.File, testCase.Line = CallerLocation(skip+1, suite.LocationRoot)
+}
@@ -0,0 +1,64 @@
+package report
+
+import (
+	"errors"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// thisFile returns the name of this file, as runtime.Caller reports it
+func thisFile() string {
+	_, file, _, _ := runtime.Caller(0)
+	return file
+}
+
+func TestCallerLocation(t *testing.T) {
+	_, _, line, _ := runtime.Caller(0)
+	file, actual := CallerLocation(0, "")
+
+	assert.Equal(t, thisFile(), file)
+	assert.Equal(t, line+1, actual)
+}
+
+func TestCallerLocation_Root(t *testing.T) {
+	root := filepath.Dir(filepath.Dir(thisFile()))
+	file, _ := CallerLocation(0, root)
+	assert.Equal(t, "report/location_test.go", file)
+
+	file, _ = CallerLocation(0, filepath.Join(root, "cmd"))
+	assert.Equal(t, thisFile(), file)
+}
+
+func TestCaptureLocation(t *testing.T) {
+	suite := NewAnonymousTestSuite()
+	assert.Nil(t, suite.AddTestCase(NewTestCase("", "name", "")))
+	assert.Empty(t, suite.TestCases[0].File)
+
+	suite.CaptureLocation = true
+	_, _, line, _ := runtime.Caller(0)
+	assert.Nil(t, suite.AddTestCase(NewTestCase("", "name", "")))
+	located := NewAnonymousTestCase()
+	located.SetLocation("file_test.go", 1)
+	assert.Nil(t, suite.AddTestCase(located))
+
+	assert.Equal(t, thisFile(), suite.TestCases[1].File)
+	assert.Equal(t, line+1, suite.TestCases[1].Line)
+	assert.Equal(t, "file_test.go", located.File)
+
+	_, _, line, _ = runtime.Caller(0)
+	run := suite.Run("run", "", func(*TestCase) error { return nil })
+	retried := suite.RunWithRetry("retry", "", NewRetryPolicy(1), func(*TestCase) error { return nil })
+	setup := suite.Setup(func() error { return errors.New("setup failed") })
+
+	assert.Equal(t, line+1, run.Line)
+	assert.Equal(t, line+2, retried.Line)
+	assert.Equal(t, line+3, setup.Line)
+	assert.Equal(t, thisFile(), setup.File)
+
+	suite.LocationRoot = filepath.Dir(filepath.Dir(thisFile()))
+	run = suite.Run("run", "", func(*TestCase) error { return nil })
+	assert.Equal(t, "report/location_test.go", run.File)
+}
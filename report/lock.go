@@ -0,0 +1,112 @@
+package report
+
+import "sync"
+
+// Every TestSuites, TestSuite, and TestCase has its own lock, so reports that
+// are built at the same time don't block each other. Builder methods (eg:
+// TestSuites.AddTestSuite, TestSuite.AddTestCase, TestCase.AddFailure) and the
+// output writers hold the lock of the element they change. Methods that read
+// or calculate a whole report, such as Snapshot, hold the locks of all its
+// elements, which are always taken from the report down to its test cases so
+// they can't deadlock with the builders. Formatters render a Snapshot, so they
+// don't hold any lock while the report is marshalled. Fields that are changed
+// directly aren't guarded.
+
+// treeLock holds the locks of the elements of a report tree. Elements shared by
+// several parents are only locked once.
+type treeLock struct {
+	write bool
+	held  map[*sync.RWMutex]bool
+	order []*sync.RWMutex
+}
+
+// newTreeLock returns a treeLock that takes write locks if write is set, or
+// read locks otherwise
+func newTreeLock(write bool) *treeLock {
+	return &treeLock{
+		write: write,
+		held:  map[*sync.RWMutex]bool{},
+	}
+}
+
+// lock takes m unless it is already held
+func (l *treeLock) lock(m *sync.RWMutex) {
+	if l.held[m] {
+		return
+	}
+
+	if l.write {
+		m.Lock()
+	} else {
+		m.RLock()
+	}
+
+	l.held[m] = true
+	l.order = append(l.order, m)
+}
+
+// testSuites locks the report, its suites, and their test cases
+func (l *treeLock) testSuites(suites *TestSuites) {
+	l.lock(&suites.mu)
+	for _, suite := range suites.TestSuites {
+		l.testSuite(suite)
+	}
+}
+
+// testSuite locks the suite, its test cases, and its nested suites
+func (l *treeLock) testSuite(suite *TestSuite) {
+	l.lock(&suite.mu)
+	for _, testCase := range suite.TestCases {
+		l.lock(&testCase.mu)
+	}
+
+	for _, nested := range suite.TestSuites {
+		l.testSuite(nested)
+	}
+}
+
+// unlock releases all the locks, children first
+func (l *treeLock) unlock() {
+	for i := len(l.order) - 1; i >= 0; i-- {
+		if l.write {
+			l.order[i].Unlock()
+		} else {
+			l.order[i].RUnlock()
+		}
+	}
+
+	l.held = map[*sync.RWMutex]bool{}
+	l.order = nil
+}
+
+// lockAll takes the write locks of the report and all its elements and returns
+// the function releasing them
+func (suites *TestSuites) lockAll() func() {
+	l := newTreeLock(true)
+	l.testSuites(suites)
+	return l.unlock
+}
+
+// rlockAll takes the read locks of the report and all its elements and returns
+// the function releasing them
+func (suites *TestSuites) rlockAll() func() {
+	l := newTreeLock(false)
+	l.testSuites(suites)
+	return l.unlock
+}
+
+// lockAll takes the write locks of the suite and all its elements and returns
+// the function releasing them
+func (suite *TestSuite) lockAll() func() {
+	l := newTreeLock(true)
+	l.testSuite(suite)
+	return l.unlock
+}
+
+// rlockAll takes the read locks of the suite and all its elements and returns
+// the function releasing them
+func (suite *TestSuite) rlockAll() func() {
+	l := newTreeLock(false)
+	l.testSuite(suite)
+	return l.unlock
+}
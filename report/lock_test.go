@@ -0,0 +1,140 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// These tests are meant to be run with the race detector, eg:
+// go test -race ./...
+
+func TestConcurrentAddTestCase(t *testing.T) {
+	suites := NewAnonymousTestSuites()
+	suite := NewAnonymousTestSuite()
+	err := suites.AddTestSuite(suite)
+	assert.Nil(t, err)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			testCase := NewTestCase(fmt.Sprintf("case#%d", i), "name", "class")
+			testCase.Start()
+			assert.Nil(t, suite.AddTestCase(testCase))
+
+			for j := 0; j < 10; j++ {
+				testCase.AddFailure(NewAnonymousFailure("failure"))
+				testCase.AddError(NewAnonymousError("error"))
+				testCase.SetProperty("attempt", fmt.Sprint(j))
+				fmt.Fprintln(testCase.SystemOutWriter(), "output")
+			}
+
+			testCase.End()
+		}(i)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := suites.MakeReport()
+			assert.Nil(t, err)
+		}()
+	}
+	wg.Wait()
+
+	_, err = suites.MakeReport()
+	assert.Nil(t, err)
+	assert.Equal(t, 50, suites.Tests)
+	assert.Equal(t, 50, suites.Failures)
+	assert.Equal(t, 50, suites.Errors)
+	assert.Equal(t, 500, suites.FailureElements())
+	assert.Equal(t, 500, suites.ErrorElements())
+}
+
+func TestConcurrentAddTestSuite(t *testing.T) {
+	suites := NewAnonymousTestSuites()
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			suite := NewTestSuite(fmt.Sprintf("suite#%d", i), "name")
+			assert.Nil(t, suites.AddTestSuite(suite))
+			assert.Nil(t, suite.AddTestCase(NewAnonymousTestCase()))
+			suite.SetProperty("shard", fmt.Sprint(i))
+		}(i)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := suites.MakeReport()
+			assert.Nil(t, err)
+		}()
+	}
+	wg.Wait()
+
+	_, err := suites.MakeReport()
+	assert.Nil(t, err)
+	assert.Equal(t, 50, len(suites.TestSuites))
+	assert.Equal(t, 50, suites.Tests)
+}
+
+func TestConcurrentReports(t *testing.T) {
+	busy := NewAnonymousTestSuites()
+	assert.Nil(t, busy.AddTestSuite(NewAnonymousTestSuite()))
+	unlock := busy.lockAll()
+	defer unlock()
+
+	suites := NewAnonymousTestSuites()
+	suite := NewAnonymousTestSuite()
+	assert.Nil(t, suites.AddTestSuite(suite))
+	assert.Nil(t, suite.AddTestCase(NewTestCase("case", "name", "class")))
+
+	_, err := suites.MakeReport()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, suites.Tests)
+}
+
+func TestConcurrentSnapshot(t *testing.T) {
+	suites := NewAnonymousTestSuites()
+	suite := NewAnonymousTestSuite()
+	assert.Nil(t, suites.AddTestSuite(suite))
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			testCase := NewTestCase(fmt.Sprintf("case#%d", i), "name", "class")
+			assert.Nil(t, suite.AddTestCase(testCase))
+			testCase.AddFailure(NewAnonymousFailure("failure"))
+			suite.SetProperty("last", fmt.Sprint(i))
+			fmt.Fprintln(suite.SystemOutWriter(), "output")
+		}(i)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			snapshot := suites.Snapshot()
+			for _, testCase := range snapshot.TestSuites[0].TestCases {
+				testCase.Failures = nil
+			}
+			_, err := json.Marshal(snapshot)
+			assert.Nil(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 50, suites.FailureElements())
+}
@@ -0,0 +1,147 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MakeMarkdown generates a Markdown summary of the report, suitable for merge
+// request comments and job summaries. It has a table with the counters and
+// time of each suite, followed by the details of every failed test case.
+// Nested suites are shown flattened, with their names joined by " / ". All
+// values are automatically calculated when calling this method.
+func (suites *TestSuites) MakeMarkdown() ([]byte, error) {
+	summary := suites.Snapshot().summary()
+
+	md := &strings.Builder{}
+	fmt.Fprintf(md, "## %s\n\n", markdownText(summary.title()))
+	fmt.Fprintf(
+		md,
+		"**%d tests**: %d passed, %d failed, %d errored, %d skipped in %s\n\n",
+		summary.Tests,
+		summary.passed(),
+		summary.Failures,
+		summary.Errors,
+		summary.Skipped,
+		durationLabel(summary.Time, summary.Precision),
+	)
+
+	md.WriteString("| Suite | Tests | Passed | Failures | Errors | Skipped | Time |\n")
+	md.WriteString("| :---- | ----: | -----: | -------: | -----: | ------: | ---: |\n")
+	for _, suite := range summary.TestSuites {
+		fmt.Fprintf(
+			md,
+			"| %s | %d | %d | %d | %d | %d | %s |\n",
+			markdownCell(displayName(suite.Name, suite.ID)),
+			suite.Tests,
+			suite.passed(),
+			suite.Failures,
+			suite.Errors,
+			suite.Skipped,
+			durationLabel(suite.Time, summary.Precision),
+		)
+	}
+
+	details := &strings.Builder{}
+	for _, suite := range summary.TestSuites {
+		for _, testCase := range suite.TestCases {
+			if testCase.passed() {
+				continue
+			}
+
+			writeMarkdownTestCase(details, suite, testCase)
+		}
+	}
+
+	if details.Len() > 0 {
+		md.WriteString("\n### Failed tests\n")
+		md.WriteString(details.String())
+	}
+
+	return []byte(md.String()), nil
+}
+
+// writeMarkdownTestCase writes the failures and errors of a test case
+func writeMarkdownTestCase(md *strings.Builder, suite *TestSuite, testCase *TestCase) {
+	fmt.Fprintf(
+		md,
+		"\n#### %s\n",
+		markdownText(displayName(suite.Name, suite.ID)+summarySeparator+displayName(testCase.Name, testCase.ID)),
+	)
+
+	for _, f := range testCase.Failures {
+		writeMarkdownProblem(md, "Failure", f.Message, f.Type, f.Content)
+	}
+
+	for _, e := range testCase.Errors {
+		writeMarkdownProblem(md, "Error", e.Message, e.Type, e.Content)
+	}
+}
+
+// writeMarkdownProblem writes a failure or error, with its content in a code
+// block
+func writeMarkdownProblem(md *strings.Builder, kind string, message string, problemType string, content string) {
+	md.WriteString("\n**" + kind + "**")
+	if len(message) > 0 {
+		md.WriteString(": " + markdownText(message))
+	}
+	if len(problemType) > 0 {
+		md.WriteString(" (" + markdownText(problemType) + ")")
+	}
+	md.WriteString("\n")
+
+	content = strings.TrimRight(content, "\n")
+	if len(content) == 0 {
+		return
+	}
+
+	fence := markdownFence(content)
+	md.WriteString("\n" + fence + "\n" + content + "\n" + fence + "\n")
+}
+
+// markdownText returns s in a single line with the characters that have a
+// meaning in Markdown escaped
+func markdownText(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	return markdownEscaper.Replace(s)
+}
+
+// markdownCell returns s escaped to be used in a table cell
+func markdownCell(s string) string {
+	return strings.ReplaceAll(markdownText(s), "|", `\|`)
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	"*", `\*`,
+	"_", `\_`,
+	"<", `\<`,
+	">", `\>`,
+	"[", `\[`,
+	"]", `\]`,
+	"#", `\#`,
+)
+
+// markdownFence returns a code fence longer than any run of backticks in
+// content
+func markdownFence(content string) string {
+	longest, current := 0, 0
+	for _, r := range content {
+		if r != '`' {
+			current = 0
+			continue
+		}
+
+		current++
+		if current > longest {
+			longest = current
+		}
+	}
+
+	if longest < 3 {
+		return "```"
+	}
+
+	return strings.Repeat("`", longest+1)
+}
@@ -0,0 +1,45 @@
+package report
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMakeMarkdown(t *testing.T) {
+	suites, err := LoadReport(filepath.Join("testdata", "summary.xml"))
+	assert.Nil(t, err)
+
+	actual, err := suites.MakeMarkdown()
+	assert.Nil(t, err)
+
+	expected := mustLoadFile("make_markdown_expected.md")
+	assert.Equal(t, string(expected), string(actual))
+}
+
+func TestMakeMarkdown_Empty(t *testing.T) {
+	actual, err := NewAnonymousTestSuites().MakeMarkdown()
+	assert.Nil(t, err)
+
+	assert.Contains(t, string(actual), "## Test report\n")
+	assert.Contains(t, string(actual), "**0 tests**")
+	assert.NotContains(t, string(actual), "Failed tests")
+}
+
+func TestMakeMarkdown_Precision(t *testing.T) {
+	suites := newTestSuites(t, "id", "id")
+	suites.TestSuites[0].TestCases[0].Time = Duration(1234567 * time.Microsecond)
+	suites.Precision = 6
+
+	actual, err := suites.MakeMarkdown()
+	assert.Nil(t, err)
+	assert.Contains(t, string(actual), "skipped in 1.234567s")
+	assert.Contains(t, string(actual), "| 1.234567s |")
+}
+
+func TestMarkdownFence(t *testing.T) {
+	assert.Equal(t, "```", markdownFence("no backticks"))
+	assert.Equal(t, "````", markdownFence("```go\n```"))
+}
@@ -0,0 +1,243 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MergePolicy defines how TestSuites.Merge handles a test suite whose ID is
+// already used by a suite in the report being merged into.
+type MergePolicy int
+
+const (
+	// MergePolicyFail makes Merge return an error when suite IDs collide.
+	MergePolicyFail MergePolicy = iota
+	// MergePolicyRename renames colliding suites by appending a numeric suffix
+	// to their IDs, eg: "id" becomes "id-1".
+	MergePolicyRename
+	// MergePolicyMerge adds the test cases and nested suites of colliding suites
+	// to the existing suite with the same ID. Test case and nested suite IDs
+	// must still be unique within the resulting suite. The earliest timestamp
+	// is kept and wall-clock times are added up. Properties are set in the
+	// existing suite, replacing the values of properties with the same name,
+	// system-out and system-err are appended, and the package and hostname
+	// are kept if the existing suite has none.
+	MergePolicyMerge
+)
+
+// Merge adds all test suites of other to suites. Suites without an ID are
+// always added. Suites with an ID that is already present are handled according
+// to the given policy. The report properties of other are set in suites,
+// replacing the values of properties with the same name. Collisions are checked
+// before anything is changed, so suites is left untouched when an error is
+// returned. Suites and test cases of other are added by reference and may be
+// renamed, so other should not be used after merging, nor changed or rendered
+// from other goroutines while it is merged. All values are recalculated after
+// merging.
+func (suites *TestSuites) Merge(other *TestSuites, policy MergePolicy) error {
+	l := newTreeLock(true)
+	l.testSuites(suites)
+	l.testSuites(other)
+	defer l.unlock()
+
+	if err := suites.checkMerge(other, policy); err != nil {
+		return err
+	}
+
+	suites.Properties = mergeProperties(suites.Properties, other.Properties)
+
+	for _, suite := range other.TestSuites {
+		existing := suites.findTestSuite(suite.ID)
+		if existing == nil {
+			suites.TestSuites = append(suites.TestSuites, suite)
+			continue
+		}
+
+		switch policy {
+		case MergePolicyRename:
+			suite.ID = suites.uniqueTestSuiteID(suite.ID)
+			suites.TestSuites = append(suites.TestSuites, suite)
+		case MergePolicyMerge:
+			if existing.WallTime != 0 || suite.WallTime != 0 {
+				existing.WallTime = existing.totalTime() + suite.totalTime()
+			}
+			if suite.Timestamp.before(existing.Timestamp) {
+				existing.Timestamp = suite.Timestamp
+			}
+			existing.mergeDetails(suite)
+			existing.TestCases = append(existing.TestCases, suite.TestCases...)
+			existing.TestSuites = append(existing.TestSuites, suite.TestSuites...)
+		}
+	}
+
+	suites.resolve()
+	return nil
+}
+
+// mergeDetails adds the properties, output, package, and hostname of other to
+// the suite, as described by MergePolicyMerge
+func (suite *TestSuite) mergeDetails(other *TestSuite) {
+	suite.Properties = mergeProperties(suite.Properties, other.Properties)
+	suite.SystemOut = joinOutput(suite.SystemOut, other.SystemOut)
+	suite.SystemErr = joinOutput(suite.SystemErr, other.SystemErr)
+
+	if len(suite.Package) == 0 {
+		suite.Package = other.Package
+	}
+
+	if len(suite.Hostname) == 0 {
+		suite.Hostname = other.Hostname
+	}
+}
+
+// mergeProperties sets the properties of other in properties, which is created
+// if needed, and returns it
+func mergeProperties(properties *Properties, other *Properties) *Properties {
+	if other == nil {
+		return properties
+	}
+
+	if properties == nil {
+		properties = NewProperties()
+	}
+
+	for _, p := range other.Properties {
+		properties.Set(p.Name, p.Value)
+	}
+
+	return properties
+}
+
+// joinOutput returns other appended to output, on a new line
+func joinOutput(output string, other string) string {
+	if len(output) == 0 {
+		return other
+	}
+
+	if len(other) == 0 {
+		return output
+	}
+
+	if !strings.HasSuffix(output, "\n") {
+		output += "\n"
+	}
+
+	return output + other
+}
+
+// checkMerge returns an error if merging other with the given policy would
+// fail
+func (suites *TestSuites) checkMerge(other *TestSuites, policy MergePolicy) error {
+	switch policy {
+	case MergePolicyFail, MergePolicyRename, MergePolicyMerge:
+	default:
+		return fmt.Errorf("cannot merge test suites: unknown merge policy %d", policy)
+	}
+
+	seen := map[string]*TestSuite{}
+	for _, suite := range other.TestSuites {
+		if len(suite.ID) == 0 {
+			continue
+		}
+
+		if _, ok := seen[suite.ID]; ok && policy != MergePolicyRename {
+			return fmt.Errorf(
+				"cannot merge test suites: merged suites contain more than one suite with ID=%s",
+				suite.ID,
+			)
+		}
+		seen[suite.ID] = suite
+
+		existing := suites.findTestSuite(suite.ID)
+		if existing == nil {
+			continue
+		}
+
+		switch policy {
+		case MergePolicyFail:
+			return fmt.Errorf(
+				"cannot merge test suites: suites ID=%s already contains a suite with ID=%s",
+				suites.ID,
+				suite.ID,
+			)
+		case MergePolicyMerge:
+			if err := checkTestCaseIDs(existing, suite); err != nil {
+				return err
+			}
+			if err := checkNestedSuiteIDs(existing, suite); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkTestCaseIDs returns an error if a test case of other has the same ID as
+// a test case of suite
+func checkTestCaseIDs(suite *TestSuite, other *TestSuite) error {
+	ids := map[string]bool{}
+	for _, c := range suite.TestCases {
+		ids[c.ID] = true
+	}
+
+	for _, c := range other.TestCases {
+		if len(c.ID) > 0 && ids[c.ID] {
+			return fmt.Errorf(
+				"cannot merge test suites: suite ID=%s already contains a case with ID=%s",
+				suite.ID,
+				c.ID,
+			)
+		}
+	}
+
+	return nil
+}
+
+// checkNestedSuiteIDs returns an error if a nested suite of other has the same
+// ID as a nested suite of suite
+func checkNestedSuiteIDs(suite *TestSuite, other *TestSuite) error {
+	ids := map[string]bool{}
+	for _, s := range suite.TestSuites {
+		ids[s.ID] = true
+	}
+
+	for _, s := range other.TestSuites {
+		if len(s.ID) > 0 && ids[s.ID] {
+			return fmt.Errorf(
+				"cannot merge test suites: suite ID=%s already contains a nested suite with ID=%s",
+				suite.ID,
+				s.ID,
+			)
+		}
+	}
+
+	return nil
+}
+
+// findTestSuite returns the suite with the given id or nil if there is none.
+// Suites without ID are never returned.
+func (suites *TestSuites) findTestSuite(id string) *TestSuite {
+	if len(id) == 0 {
+		return nil
+	}
+
+	for _, suite := range suites.TestSuites {
+		if suite.ID == id {
+			return suite
+		}
+	}
+
+	return nil
+}
+
+// uniqueTestSuiteID returns id with the lowest numeric suffix that isn't used
+// by any suite
+func (suites *TestSuites) uniqueTestSuiteID(id string) string {
+	for i := 1; ; i++ {
+		candidate := fmt.Sprintf("%s-%d", id, i)
+		if suites.findTestSuite(candidate) == nil {
+			return candidate
+		}
+	}
+}
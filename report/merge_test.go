@@ -0,0 +1,180 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerge_NoCollision(t *testing.T) {
+	actual := newTestSuites(t, "1", "a")
+	other := newTestSuites(t, "2", "b", "c")
+	err := other.AddTestSuite(NewAnonymousTestSuite())
+	assert.Nil(t, err)
+
+	err = actual.Merge(other, MergePolicyFail)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 3, len(actual.TestSuites))
+	assert.Equal(t, 3, actual.Tests)
+}
+
+func TestMerge_Fail(t *testing.T) {
+	actual := newTestSuites(t, "1", "a")
+	other := newTestSuites(t, "1", "b")
+
+	err := actual.Merge(other, MergePolicyFail)
+	assert.NotNil(t, err)
+
+	assert.Equal(t, 1, len(actual.TestSuites))
+	assert.Equal(t, 1, len(actual.TestSuites[0].TestCases))
+}
+
+func TestMerge_Rename(t *testing.T) {
+	actual := newTestSuites(t, "1", "a")
+	err := actual.AddTestSuite(NewTestSuite("1-1", "name"))
+	assert.Nil(t, err)
+	other := newTestSuites(t, "1", "b")
+
+	err = actual.Merge(other, MergePolicyRename)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 3, len(actual.TestSuites))
+	assert.Equal(t, "1-2", actual.TestSuites[2].ID)
+	assert.Equal(t, 2, actual.Tests)
+}
+
+func TestMerge_Merge(t *testing.T) {
+	actual := newTestSuites(t, "1", "a")
+	other := newTestSuites(t, "1", "b", "c")
+
+	err := actual.Merge(other, MergePolicyMerge)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1, len(actual.TestSuites))
+	assert.Equal(t, 3, actual.TestSuites[0].Tests)
+	assert.Equal(t, 3, actual.Tests)
+}
+
+func TestMerge_MergeTimes(t *testing.T) {
+	actual := newTestSuites(t, "1", "a")
+	actual.TestSuites[0].TestCases[0].Time = 1000
+	actual.TestSuites[0].Timestamp = Timestamp(time.Date(2021, 3, 4, 15, 4, 5, 0, time.UTC))
+	other := newTestSuites(t, "1", "b")
+	other.TestSuites[0].WallTime = 3000
+	other.TestSuites[0].Timestamp = Timestamp(time.Date(2021, 3, 4, 15, 0, 0, 0, time.UTC))
+
+	err := actual.Merge(other, MergePolicyMerge)
+	assert.Nil(t, err)
+
+	assert.Equal(t, Duration(4000), actual.TestSuites[0].WallTime)
+	assert.Equal(t, Duration(4000), actual.TestSuites[0].Time)
+	assert.Equal(t, other.TestSuites[0].Timestamp, actual.TestSuites[0].Timestamp)
+}
+
+func TestMerge_MergeProperties(t *testing.T) {
+	actual := newTestSuites(t, "1", "a")
+	actual.SetProperty("sha", "abc")
+	actual.TestSuites[0].SetProperty("db", "postgres")
+	other := newTestSuites(t, "1", "b")
+	other.SetProperty("sha", "def")
+	other.SetProperty("shard", "2")
+	other.TestSuites[0].SetProperty("db", "mysql")
+	other.TestSuites[0].SetProperty("cache", "redis")
+
+	err := actual.Merge(other, MergePolicyMerge)
+	assert.Nil(t, err)
+
+	assert.Equal(t, &Properties{Properties: []*Property{
+		NewProperty("sha", "def"),
+		NewProperty("shard", "2"),
+	}}, actual.Properties)
+	assert.Equal(t, &Properties{Properties: []*Property{
+		NewProperty("db", "mysql"),
+		NewProperty("cache", "redis"),
+	}}, actual.TestSuites[0].Properties)
+}
+
+func TestMerge_MergeOutput(t *testing.T) {
+	actual := newTestSuites(t, "1", "a")
+	actual.TestSuites[0].SystemOut = "shard 1"
+	other := newTestSuites(t, "1", "b")
+	other.TestSuites[0].SystemOut = "shard 2\n"
+	other.TestSuites[0].SystemErr = "warning"
+
+	err := actual.Merge(other, MergePolicyMerge)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "shard 1\nshard 2\n", actual.TestSuites[0].SystemOut)
+	assert.Equal(t, "warning", actual.TestSuites[0].SystemErr)
+}
+
+func TestMerge_MergePackageHostname(t *testing.T) {
+	actual := newTestSuites(t, "1", "a")
+	actual.TestSuites[0].Package = "api"
+	other := newTestSuites(t, "1", "b")
+	other.TestSuites[0].Package = "other"
+	other.TestSuites[0].Hostname = "runner-2"
+
+	err := actual.Merge(other, MergePolicyMerge)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "api", actual.TestSuites[0].Package)
+	assert.Equal(t, "runner-2", actual.TestSuites[0].Hostname)
+}
+
+func TestMerge_ReportProperties(t *testing.T) {
+	policies := []MergePolicy{MergePolicyFail, MergePolicyRename, MergePolicyMerge}
+
+	for _, policy := range policies {
+		actual := newTestSuites(t, "1", "a")
+		other := newTestSuites(t, "2", "b")
+		other.SetProperty("sha", "abc")
+
+		err := actual.Merge(other, policy)
+		assert.Nil(t, err)
+
+		value, ok := actual.GetProperty("sha")
+		assert.True(t, ok, policy)
+		assert.Equal(t, "abc", value, policy)
+	}
+}
+
+func TestMerge_MergeNested(t *testing.T) {
+	actual := newTestSuites(t, "1", "a")
+	assert.Nil(t, actual.TestSuites[0].AddTestSuite(NewTestSuite("nested", "name")))
+	other := newTestSuites(t, "1", "b")
+	assert.Nil(t, other.TestSuites[0].AddTestSuite(NewTestSuite("nested", "name")))
+
+	err := actual.Merge(other, MergePolicyMerge)
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, len(actual.TestSuites[0].TestSuites))
+
+	other.TestSuites[0].TestSuites[0].ID = "other"
+	other.TestSuites[0].TestSuites[0].TestCases = []*TestCase{NewTestCase("", "c", "")}
+	err = actual.Merge(other, MergePolicyMerge)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(actual.TestSuites[0].TestSuites))
+	assert.Equal(t, 3, actual.Tests)
+}
+
+func TestMerge_MergeCaseCollision(t *testing.T) {
+	actual := newTestSuites(t, "1", "a")
+	other := newTestSuites(t, "1", "b", "a")
+
+	err := actual.Merge(other, MergePolicyMerge)
+	assert.NotNil(t, err)
+
+	assert.Equal(t, 1, len(actual.TestSuites[0].TestCases))
+}
+
+func TestMerge_UnknownPolicy(t *testing.T) {
+	actual := newTestSuites(t, "1", "a")
+	other := newTestSuites(t, "2", "b")
+
+	err := actual.Merge(other, MergePolicy(42))
+	assert.NotNil(t, err)
+
+	assert.Equal(t, 1, len(actual.TestSuites))
+}
@@ -0,0 +1,25 @@
+package report
+
+import (
+	"io"
+	"sync"
+)
+
+// outputWriter is an io.Writer that appends everything written to it to the
+// string it points to, holding the lock of the element the string belongs to.
+// It is used to expose system-out and system-err as writers.
+type outputWriter struct {
+	output *string
+	mu     *sync.RWMutex
+}
+
+// Write implements io.Writer
+func (w outputWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	*w.output += string(p)
+	return len(p), nil
+}
+
+var _ io.Writer = outputWriter{}
@@ -0,0 +1,19 @@
+package report
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutputWriter(t *testing.T) {
+	output := "a"
+	w := outputWriter{output: &output, mu: &sync.RWMutex{}}
+
+	n, err := w.Write([]byte("bc"))
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, "abc", output)
+}
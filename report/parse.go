@@ -0,0 +1,221 @@
+package report
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// parsedTestSuites is the decoding counterpart of TestSuites. Counters are not
+// decoded since they are recalculated after parsing.
+type parsedTestSuites struct {
+	ID         string             `xml:"id,attr"`
+	Name       string             `xml:"name,attr"`
+	Timestamp  Timestamp          `xml:"timestamp,attr"`
+	Hostname   string             `xml:"hostname,attr"`
+	Properties *Properties        `xml:"properties"`
+	TestSuites []*parsedTestSuite `xml:"testsuite"`
+}
+
+// parsedTestSuite is the decoding counterpart of TestSuite. Some dialects nest
+// testsuite tags, so they are decoded recursively. The time is decoded as the
+// wall-clock time of the suite, since it may include time spent outside test
+// cases, eg: setup.
+type parsedTestSuite struct {
+	ID         string             `xml:"id,attr"`
+	Name       string             `xml:"name,attr"`
+	Package    string             `xml:"package,attr"`
+	Timestamp  Timestamp          `xml:"timestamp,attr"`
+	Hostname   string             `xml:"hostname,attr"`
+	Time       string             `xml:"time,attr"`
+	Properties *Properties        `xml:"properties"`
+	TestCases  []*parsedTestCase  `xml:"testcase"`
+	TestSuites []*parsedTestSuite `xml:"testsuite"`
+	SystemOut  []string           `xml:"system-out"`
+	SystemErr  []string           `xml:"system-err"`
+}
+
+// parsedTestCase is the decoding counterpart of TestCase
+type parsedTestCase struct {
+	ID            string      `xml:"id,attr"`
+	Name          string      `xml:"name,attr"`
+	Time          string      `xml:"time,attr"`
+	Classname     string      `xml:"classname,attr"`
+	File          string      `xml:"file,attr"`
+	Line          int         `xml:"line,attr"`
+	Content       string      `xml:",chardata"`
+	Properties    *Properties `xml:"properties"`
+	Skipped       *Skipped    `xml:"skipped"`
+	Failures      []*Failure  `xml:"failure"`
+	Errors        []*Error    `xml:"error"`
+	FlakyFailures []*Rerun    `xml:"flakyFailure"`
+	FlakyErrors   []*Rerun    `xml:"flakyError"`
+	RerunFailures []*Rerun    `xml:"rerunFailure"`
+	RerunErrors   []*Rerun    `xml:"rerunError"`
+	SystemOut     []string    `xml:"system-out"`
+	SystemErr     []string    `xml:"system-err"`
+}
+
+// ParseReport reads a JUnit XML report from r. Both testsuites and bare
+// testsuite root tags are accepted. Nested test suites are kept nested, use
+// TestSuites.Flatten to move them to the root. When a test case or suite has
+// several system-out or system-err tags, their contents are joined with a line
+// break. All calculated values are recalculated, so the counters found in the
+// XML are ignored. The time of each suite is kept as its WallTime. Duplicated
+// suite and test case IDs are kept as they are, see TestSuites.Validate.
+func ParseReport(r io.Reader) (*TestSuites, error) {
+	decoder := xml.NewDecoder(r)
+
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("cannot parse report: no root tag found")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse report: %w", err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch start.Name.Local {
+		case "testsuites":
+			parsed := &parsedTestSuites{}
+			if err := decoder.DecodeElement(parsed, &start); err != nil {
+				return nil, fmt.Errorf("cannot parse report: %w", err)
+			}
+			return parsed.toTestSuites()
+		case "testsuite":
+			parsed := &parsedTestSuite{}
+			if err := decoder.DecodeElement(parsed, &start); err != nil {
+				return nil, fmt.Errorf("cannot parse report: %w", err)
+			}
+			root := &parsedTestSuites{
+				TestSuites: []*parsedTestSuite{parsed},
+			}
+			return root.toTestSuites()
+		default:
+			return nil, fmt.Errorf(
+				"cannot parse report: unexpected root tag %s",
+				start.Name.Local,
+			)
+		}
+	}
+}
+
+// LoadReport reads a JUnit XML report from the given file name. See
+// ParseReport for details.
+func LoadReport(filename string) (*TestSuites, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ParseReport(f)
+}
+
+func (parsed *parsedTestSuites) toTestSuites() (*TestSuites, error) {
+	suites := NewTestSuites(parsed.ID, parsed.Name)
+	suites.Timestamp = parsed.Timestamp
+	suites.Hostname = parsed.Hostname
+	suites.Properties = parsed.Properties
+
+	for _, parsedSuite := range parsed.TestSuites {
+		suite, err := parsedSuite.toTestSuite()
+		if err != nil {
+			return nil, err
+		}
+
+		suites.TestSuites = append(suites.TestSuites, suite)
+	}
+
+	suites.resolve()
+	return suites, nil
+}
+
+// toTestSuite returns the suite with its test cases and nested suites
+func (parsed *parsedTestSuite) toTestSuite() (*TestSuite, error) {
+	suite := NewTestSuite(parsed.ID, parsed.Name)
+	suite.Package = parsed.Package
+	suite.Timestamp = parsed.Timestamp
+	suite.Hostname = parsed.Hostname
+	suite.Properties = parsed.Properties
+	suite.SystemOut = strings.Join(parsed.SystemOut, "\n")
+	suite.SystemErr = strings.Join(parsed.SystemErr, "\n")
+
+	wallTime, err := parseTime(parsed.Time)
+	if err != nil {
+		return nil, err
+	}
+	suite.WallTime = wallTime
+
+	for _, parsedCase := range parsed.TestCases {
+		testCase, err := parsedCase.toTestCase()
+		if err != nil {
+			return nil, err
+		}
+
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+
+	for _, parsedNested := range parsed.TestSuites {
+		nested, err := parsedNested.toTestSuite()
+		if err != nil {
+			return nil, err
+		}
+
+		suite.TestSuites = append(suite.TestSuites, nested)
+	}
+
+	return suite, nil
+}
+
+func (parsed *parsedTestCase) toTestCase() (*TestCase, error) {
+	testCase := NewTestCase(parsed.ID, parsed.Name, parsed.Classname)
+	testCase.File = parsed.File
+	testCase.Line = parsed.Line
+
+	d, err := parseTime(parsed.Time)
+	if err != nil {
+		return nil, err
+	}
+	testCase.Time = d
+	testCase.Properties = parsed.Properties
+
+	testCase.SetContent(strings.TrimSpace(parsed.Content))
+	testCase.SystemOut = strings.Join(parsed.SystemOut, "\n")
+	testCase.SystemErr = strings.Join(parsed.SystemErr, "\n")
+	testCase.SetSkipped(parsed.Skipped)
+
+	for _, f := range parsed.Failures {
+		testCase.AddFailure(f)
+	}
+
+	for _, e := range parsed.Errors {
+		testCase.AddError(e)
+	}
+
+	testCase.FlakyFailures = parsed.FlakyFailures
+	testCase.FlakyErrors = parsed.FlakyErrors
+	testCase.RerunFailures = parsed.RerunFailures
+	testCase.RerunErrors = parsed.RerunErrors
+
+	return testCase, nil
+}
+
+// parseTime parses a time attribute. Some tools use a thousands separator for
+// long durations (eg: "1,234.567"), so it is removed before parsing.
+func parseTime(s string) (Duration, error) {
+	d, err := ParseDuration(strings.ReplaceAll(s, ",", ""))
+	if err != nil {
+		return 0, fmt.Errorf("cannot parse report: %w", err)
+	}
+
+	return d, nil
+}
@@ -0,0 +1,174 @@
+package report
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseReport_RoundTrip(t *testing.T) {
+	files := []string{
+		"make_report_expected.xml",
+		"make_report_skipped_expected.xml",
+		"make_report_properties_expected.xml",
+		"make_report_output_expected.xml",
+	}
+
+	for _, name := range files {
+		expected := mustLoadFile(name)
+
+		suites, err := ParseReport(bytes.NewReader(expected))
+		assert.Nil(t, err, name)
+
+		actual, err := suites.MakeReport()
+		assert.Nil(t, err, name)
+		assert.Equal(t, string(expected), string(actual), name)
+	}
+}
+
+func TestParseReport_BareTestSuite(t *testing.T) {
+	actual, err := ParseReport(bytes.NewReader(mustLoadFile("parse_bare_testsuite.xml")))
+	assert.Nil(t, err)
+
+	timestamp := Timestamp(time.Date(2021, 3, 4, 15, 4, 5, 0, time.UTC))
+	expected := &TestSuites{
+		Timestamp: timestamp,
+		Tests:     3,
+		Failures:  1,
+		Errors:    0,
+		Skipped:   1,
+		Time:      Duration(1240500 * time.Millisecond),
+		TestSuites: []*TestSuite{
+			{
+				Name:      "com.example.AppTest",
+				Timestamp: timestamp,
+				Hostname:  "ci-runner",
+				Time:      Duration(1240500 * time.Millisecond),
+				Tests:     3,
+				Failures:  1,
+				Errors:    0,
+				Skipped:   1,
+				Properties: &Properties{
+					Properties: []*Property{
+						{
+							Name:  "java.version",
+							Value: "17",
+						},
+					},
+				},
+				TestCases: []*TestCase{
+					{
+						Name:      "testOne",
+						Classname: "com.example.AppTest",
+						Time:      Duration(1234250 * time.Millisecond),
+					},
+					{
+						Name:      "testTwo",
+						Classname: "com.example.AppTest",
+						Time:      Duration(250 * time.Millisecond),
+						SystemOut: "some output",
+						Failures: []*Failure{
+							{
+								Message: "expected 1",
+								Type:    "java.lang.AssertionError",
+								Content: "stack trace",
+							},
+						},
+					},
+					{
+						Name:      "testThree",
+						Classname: "com.example.AppTest",
+						Skipped: &Skipped{
+							Message: "disabled",
+						},
+					},
+				},
+				SystemOut: "suite output",
+				WallTime:  Duration(1240500 * time.Millisecond),
+			},
+		},
+	}
+
+	assert.Equal(t, expected, actual)
+}
+
+func TestParseReport_Nested(t *testing.T) {
+	actual, err := ParseReport(bytes.NewReader(mustLoadFile("parse_nested.xml")))
+	assert.Nil(t, err)
+
+	assert.Equal(t, "nested", actual.Name)
+	assert.Equal(t, 2, actual.Tests)
+	assert.Equal(t, 1, actual.Errors)
+	assert.Equal(t, Duration(2*time.Second), actual.Time)
+	assert.Equal(t, 1, len(actual.TestSuites))
+	assert.Equal(t, "service", actual.TestSuites[0].Name)
+	assert.Equal(t, 2, actual.TestSuites[0].Tests)
+	assert.Equal(t, 1, len(actual.TestSuites[0].TestSuites))
+
+	nested := actual.TestSuites[0].TestSuites[0]
+	assert.Equal(t, "service.endpoint", nested.Name)
+	assert.Equal(t, 1, nested.Tests)
+	assert.Equal(t, "boom", nested.TestCases[0].Errors[0].Message)
+}
+
+func TestParseReport_SystemOut(t *testing.T) {
+	actual, err := ParseReport(bytes.NewReader(mustLoadFile("parse_pytest.xml")))
+	assert.Nil(t, err)
+
+	cases := actual.TestSuites[0].TestCases
+	assert.Equal(t, "stdout line", cases[0].SystemOut)
+	assert.Equal(t, "stderr line", cases[0].SystemErr)
+	assert.Equal(t, &Skipped{
+		Message: "no fixture",
+		Content: "tests/test_app.py:10: no fixture",
+	}, cases[1].Skipped)
+	assert.Equal(t, 1, actual.Skipped)
+}
+
+func TestParseReport_WholeSeconds(t *testing.T) {
+	actual, err := ParseReport(strings.NewReader(`<testsuite name="s"><testcase name="c" time="5"/></testsuite>`))
+	assert.Nil(t, err)
+
+	assert.Equal(t, Duration(5*time.Second), actual.TestSuites[0].TestCases[0].Time)
+	assert.Equal(t, Duration(5*time.Second), actual.Time)
+}
+
+func TestParseReport_DuplicatedIDs(t *testing.T) {
+	actual, err := ParseReport(strings.NewReader(`<testsuite id="s"><testcase id="1" name="a"/><testcase id="1" name="b"/></testsuite>`))
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, actual.Tests)
+	assert.Equal(t, "b", actual.TestSuites[0].TestCases[1].Name)
+
+	var errs ValidationErrors
+	assert.True(t, errors.As(actual.Validate(), &errs))
+	assert.Equal(t, "duplicated test case ID", errs[0].Message)
+}
+
+func TestParseReport_Error(t *testing.T) {
+	inputs := []string{
+		"",
+		"<report></report>",
+		`<testsuites><testsuite><testcase time="1.5s"></testcase></testsuite></testsuites>`,
+		"<testsuites>",
+	}
+
+	for _, input := range inputs {
+		_, err := ParseReport(strings.NewReader(input))
+		assert.NotNil(t, err, input)
+	}
+}
+
+func TestLoadReport(t *testing.T) {
+	actual, err := LoadReport(filepath.Join("testdata", "parse_pytest.xml"))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, actual.Tests)
+
+	_, err = LoadReport(filepath.Join("testdata", "missing.xml"))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,72 @@
+package report
+
+import "encoding/json"
+
+// Property corresponds to a property tag inside properties. It can be used to
+// record data about the environment the tests ran in, eg: git SHA or database
+// version. It has two fields: Name and Value, which map to the name and value
+// attributes.
+type Property struct {
+	Name  string `xml:"name,attr" json:"name"`
+	Value string `xml:"value,attr" json:"value"`
+}
+
+// NewProperty returns a Property with the given name and value
+func NewProperty(name string, value string) *Property {
+	return &Property{
+		Name:  name,
+		Value: value,
+	}
+}
+
+// Properties maps to a properties tag. Properties are written in the order
+// they were first set. It has one field: Properties, where each element maps to
+// its own property tag.
+type Properties struct {
+	Properties []*Property `xml:"property" json:"properties,omitempty"`
+}
+
+// NewProperties returns an empty Properties
+func NewProperties() *Properties {
+	return &Properties{}
+}
+
+// Set sets the value of the property with the given name. If the property
+// already exists its value is replaced and its position is kept, otherwise it
+// is added to the end.
+func (properties *Properties) Set(name string, value string) {
+	for _, p := range properties.Properties {
+		if p.Name == name {
+			p.Value = value
+			return
+		}
+	}
+
+	properties.Properties = append(properties.Properties, NewProperty(name, value))
+}
+
+// Get returns the value of the property with the given name and whether it
+// exists. It is safe to call Get on a nil Properties.
+func (properties *Properties) Get(name string) (string, bool) {
+	if properties == nil {
+		return "", false
+	}
+
+	for _, p := range properties.Properties {
+		if p.Name == name {
+			return p.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// MarshalJSON implements json.Marshaler. Properties are written as an array of
+// objects with name and value keys.
+func (properties *Properties) MarshalJSON() ([]byte, error) {
+	if properties.Properties == nil {
+		return []byte("[]"), nil
+	}
+
+	return json.Marshal(properties.Properties)
+}
@@ -0,0 +1,68 @@
+package report
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewProperty(t *testing.T) {
+	actual := NewProperty("name", "value")
+
+	expected := &Property{
+		Name:  "name",
+		Value: "value",
+	}
+
+	assert.Equal(t, expected, actual)
+}
+
+func TestPropertiesSet(t *testing.T) {
+	actual := NewProperties()
+	actual.Set("b", "1")
+	actual.Set("a", "2")
+	actual.Set("b", "3")
+
+	expected := &Properties{
+		Properties: []*Property{
+			{
+				Name:  "b",
+				Value: "3",
+			},
+			{
+				Name:  "a",
+				Value: "2",
+			},
+		},
+	}
+
+	assert.Equal(t, expected, actual)
+}
+
+func TestPropertiesGet(t *testing.T) {
+	actual := NewProperties()
+	actual.Set("name", "value")
+
+	value, ok := actual.Get("name")
+	assert.True(t, ok)
+	assert.Equal(t, "value", value)
+
+	_, ok = actual.Get("missing")
+	assert.False(t, ok)
+
+	var empty *Properties
+	_, ok = empty.Get("name")
+	assert.False(t, ok)
+}
+
+func TestPropertiesMarshalXML(t *testing.T) {
+	actual := NewAnonymousTestCase()
+	actual.SetProperty("name", "value")
+
+	content, err := xml.Marshal(actual)
+	assert.Nil(t, err)
+
+	expected := `<TestCase><properties><property name="name" value="value"></property></properties></TestCase>`
+	assert.Equal(t, expected, string(content))
+}
@@ -0,0 +1,195 @@
+package report
+
+import (
+	"encoding/json"
+	"encoding/xml"
+)
+
+// renderedTime is a time attribute formatted with the precision of the report
+// being rendered. It is empty, and so omitted, if the duration is 0.
+type renderedTime string
+
+// formatTime formats d with the given number of decimal places
+func formatTime(d Duration, precision int) renderedTime {
+	if d == 0 {
+		return ""
+	}
+
+	return renderedTime(d.Format(precision))
+}
+
+// MarshalJSON implements json.Marshaler. The time is written as a number.
+func (t renderedTime) MarshalJSON() ([]byte, error) {
+	return []byte(t), nil
+}
+
+// renderedTestSuites is the report as it is written, with the same elements as
+// TestSuites but with the times already formatted
+type renderedTestSuites struct {
+	XMLName    xml.Name         `xml:"testsuites" json:"-"`
+	ID         string           `xml:"id,attr,omitempty" json:"id,omitempty"`
+	Name       string           `xml:"name,attr,omitempty" json:"name,omitempty"`
+	Timestamp  Timestamp        `xml:"timestamp,attr" json:"timestamp"`
+	Hostname   string           `xml:"hostname,attr,omitempty" json:"hostname,omitempty"`
+	Tests      int              `xml:"tests,attr" json:"tests"`
+	Failures   int              `xml:"failures,attr" json:"failures"`
+	Errors     int              `xml:"errors,attr" json:"errors"`
+	Skipped    int              `xml:"skipped,attr" json:"skipped"`
+	Time       renderedTime     `xml:"time,attr,omitempty" json:"time,omitempty"`
+	Properties *Properties      `xml:"properties,omitempty" json:"properties,omitempty"`
+	TestSuites []*renderedSuite `xml:"testsuite,omitempty" json:"testsuites,omitempty"`
+}
+
+// renderedSuite is a suite as it is written, see renderedTestSuites
+type renderedSuite struct {
+	ID         string           `xml:"id,attr,omitempty" json:"id,omitempty"`
+	Name       string           `xml:"name,attr,omitempty" json:"name,omitempty"`
+	Package    string           `xml:"package,attr,omitempty" json:"package,omitempty"`
+	Timestamp  Timestamp        `xml:"timestamp,attr" json:"timestamp"`
+	Hostname   string           `xml:"hostname,attr,omitempty" json:"hostname,omitempty"`
+	Time       renderedTime     `xml:"time,attr,omitempty" json:"time,omitempty"`
+	Tests      int              `xml:"tests,attr" json:"tests"`
+	Failures   int              `xml:"failures,attr" json:"failures"`
+	Errors     int              `xml:"errors,attr" json:"errors"`
+	Skipped    int              `xml:"skipped,attr" json:"skipped"`
+	Properties *Properties      `xml:"properties,omitempty" json:"properties,omitempty"`
+	TestCases  []*renderedCase  `xml:"testcase,omitempty" json:"testcases,omitempty"`
+	TestSuites []*renderedSuite `xml:"testsuite,omitempty" json:"testsuites,omitempty"`
+	SystemOut  string           `xml:"system-out,omitempty" json:"system-out,omitempty"`
+	SystemErr  string           `xml:"system-err,omitempty" json:"system-err,omitempty"`
+}
+
+// renderedCase is a test case as it is written, see renderedTestSuites
+type renderedCase struct {
+	ID            string           `xml:"id,attr,omitempty" json:"id,omitempty"`
+	Name          string           `xml:"name,attr,omitempty" json:"name,omitempty"`
+	Time          renderedTime     `xml:"time,attr,omitempty" json:"time,omitempty"`
+	Classname     string           `xml:"classname,attr,omitempty" json:"classname,omitempty"`
+	File          string           `xml:"file,attr,omitempty" json:"file,omitempty"`
+	Line          int              `xml:"line,attr,omitempty" json:"line,omitempty"`
+	Content       string           `xml:",chardata" json:"content,omitempty"`
+	Properties    *Properties      `xml:"properties,omitempty" json:"properties,omitempty"`
+	Skipped       *Skipped         `xml:"skipped,omitempty" json:"skipped,omitempty"`
+	Failures      []*Failure       `xml:"failure" json:"failures,omitempty"`
+	Errors        []*Error         `xml:"error" json:"errors,omitempty"`
+	FlakyFailures []*renderedRerun `xml:"flakyFailure" json:"flakyFailures,omitempty"`
+	FlakyErrors   []*renderedRerun `xml:"flakyError" json:"flakyErrors,omitempty"`
+	RerunFailures []*renderedRerun `xml:"rerunFailure" json:"rerunFailures,omitempty"`
+	RerunErrors   []*renderedRerun `xml:"rerunError" json:"rerunErrors,omitempty"`
+	SystemOut     string           `xml:"system-out,omitempty" json:"system-out,omitempty"`
+	SystemErr     string           `xml:"system-err,omitempty" json:"system-err,omitempty"`
+}
+
+// renderedRerun is a rerun as it is written, see renderedTestSuites
+type renderedRerun struct {
+	Message    string       `xml:"message,attr,omitempty" json:"message,omitempty"`
+	Type       string       `xml:"type,attr,omitempty" json:"type,omitempty"`
+	Time       renderedTime `xml:"time,attr,omitempty" json:"time,omitempty"`
+	StackTrace string       `xml:"stackTrace,omitempty" json:"stackTrace,omitempty"`
+	SystemOut  string       `xml:"system-out,omitempty" json:"system-out,omitempty"`
+	SystemErr  string       `xml:"system-err,omitempty" json:"system-err,omitempty"`
+}
+
+// MarshalXML implements xml.Marshaler. The times are written with the report
+// Precision. The element is always named testsuites.
+func (suites *TestSuites) MarshalXML(encoder *xml.Encoder, _ xml.StartElement) error {
+	return encoder.Encode(suites.render())
+}
+
+// MarshalJSON implements json.Marshaler. The times are written with the report
+// Precision.
+func (suites *TestSuites) MarshalJSON() ([]byte, error) {
+	return json.Marshal(suites.render())
+}
+
+// render returns the report with its times formatted with its precision
+func (suites *TestSuites) render() *renderedTestSuites {
+	precision := suites.precision()
+	rendered := &renderedTestSuites{
+		ID:         suites.ID,
+		Name:       suites.Name,
+		Timestamp:  suites.Timestamp,
+		Hostname:   suites.Hostname,
+		Tests:      suites.Tests,
+		Failures:   suites.Failures,
+		Errors:     suites.Errors,
+		Skipped:    suites.Skipped,
+		Time:       formatTime(suites.Time, precision),
+		Properties: suites.Properties,
+	}
+	for _, suite := range suites.TestSuites {
+		rendered.TestSuites = append(rendered.TestSuites, suite.render(precision))
+	}
+
+	return rendered
+}
+
+// render returns the suite with its times formatted with the given precision
+func (suite *TestSuite) render(precision int) *renderedSuite {
+	rendered := &renderedSuite{
+		ID:         suite.ID,
+		Name:       suite.Name,
+		Package:    suite.Package,
+		Timestamp:  suite.Timestamp,
+		Hostname:   suite.Hostname,
+		Time:       formatTime(suite.Time, precision),
+		Tests:      suite.Tests,
+		Failures:   suite.Failures,
+		Errors:     suite.Errors,
+		Skipped:    suite.Skipped,
+		Properties: suite.Properties,
+		SystemOut:  suite.SystemOut,
+		SystemErr:  suite.SystemErr,
+	}
+	for _, testCase := range suite.TestCases {
+		rendered.TestCases = append(rendered.TestCases, testCase.render(precision))
+	}
+
+	for _, nested := range suite.TestSuites {
+		rendered.TestSuites = append(rendered.TestSuites, nested.render(precision))
+	}
+
+	return rendered
+}
+
+// render returns the test case with its times formatted with the given
+// precision
+func (testCase *TestCase) render(precision int) *renderedCase {
+	return &renderedCase{
+		ID:            testCase.ID,
+		Name:          testCase.Name,
+		Time:          formatTime(testCase.Time, precision),
+		Classname:     testCase.Classname,
+		File:          testCase.File,
+		Line:          testCase.Line,
+		Content:       testCase.Content,
+		Properties:    testCase.Properties,
+		Skipped:       testCase.Skipped,
+		Failures:      testCase.Failures,
+		Errors:        testCase.Errors,
+		FlakyFailures: renderReruns(testCase.FlakyFailures, precision),
+		FlakyErrors:   renderReruns(testCase.FlakyErrors, precision),
+		RerunFailures: renderReruns(testCase.RerunFailures, precision),
+		RerunErrors:   renderReruns(testCase.RerunErrors, precision),
+		SystemOut:     testCase.SystemOut,
+		SystemErr:     testCase.SystemErr,
+	}
+}
+
+// renderReruns returns the reruns with their times formatted with the given
+// precision
+func renderReruns(reruns []*Rerun, precision int) []*renderedRerun {
+	var rendered []*renderedRerun
+	for _, rerun := range reruns {
+		rendered = append(rendered, &renderedRerun{
+			Message:    rerun.Message,
+			Type:       rerun.Type,
+			Time:       formatTime(rerun.Time, precision),
+			StackTrace: rerun.StackTrace,
+			SystemOut:  rerun.SystemOut,
+			SystemErr:  rerun.SystemErr,
+		})
+	}
+
+	return rendered
+}
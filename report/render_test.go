@@ -0,0 +1,46 @@
+package report
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTestSuitesMarshalXML(t *testing.T) {
+	testCase := NewTestCase("", "name", "class")
+	testCase.Time = Duration(1500 * time.Millisecond)
+	testCase.AddFlakyFailure(&Rerun{Message: "flaky", Time: Duration(300 * time.Millisecond)})
+	suite := NewTestSuite("", "suite")
+	suite.TestCases = []*TestCase{testCase}
+	suites := NewAnonymousTestSuites()
+	suites.TestSuites = []*TestSuite{suite}
+	suites.Precision = 1
+
+	actual, err := xml.Marshal(suites)
+	assert.Nil(t, err)
+	assert.Equal(
+		t,
+		`<testsuites tests="0" failures="0" errors="0" skipped="0">`+
+			`<testsuite name="suite" tests="0" failures="0" errors="0" skipped="0">`+
+			`<testcase name="name" time="1.5" classname="class"><flakyFailure message="flaky" time="0.3"></flakyFailure></testcase>`+
+			`</testsuite></testsuites>`,
+		string(actual),
+	)
+
+	assert.Equal(t, "1.500", testCase.Time.String())
+}
+
+func TestTestSuitesMarshalJSON(t *testing.T) {
+	suites := NewAnonymousTestSuites()
+	suites.Time = Duration(1500 * time.Millisecond)
+	suites.Precision = 2
+
+	actual, err := json.Marshal(suites)
+	assert.Nil(t, err)
+	assert.Contains(t, string(actual), `"time":1.50}`)
+
+	assert.Equal(t, "1.500", suites.Time.String())
+}
@@ -0,0 +1,151 @@
+package report
+
+import "time"
+
+// Rerun corresponds to the flakyFailure, flakyError, rerunFailure, and
+// rerunError tags inside testcase, as written by Maven Surefire. Each one
+// records a failed attempt of a test that was run more than once:
+// flakyFailure and flakyError are attempts of a test that eventually passed,
+// while rerunFailure and rerunError are the reruns of a test that never passed.
+// It has the following fields:
+// Message: optional failure or error message. Maps to the message attribute.
+// Type: optional failure or error type. Maps to the type attribute.
+// Time: optional duration of the attempt. Maps to the time attribute, written
+// in seconds.
+// StackTrace: optional detailed representation of the failure or error. Maps
+// to the stackTrace tag.
+// SystemOut: optional standard output of the attempt. Maps to the system-out
+// tag.
+// SystemErr: optional standard error of the attempt. Maps to the system-err
+// tag.
+type Rerun struct {
+	Message    string   `xml:"message,attr,omitempty" json:"message,omitempty"`
+	Type       string   `xml:"type,attr,omitempty" json:"type,omitempty"`
+	Time       Duration `xml:"time,attr,omitempty" json:"time,omitempty"`
+	StackTrace string   `xml:"stackTrace,omitempty" json:"stackTrace,omitempty"`
+	SystemOut  string   `xml:"system-out,omitempty" json:"system-out,omitempty"`
+	SystemErr  string   `xml:"system-err,omitempty" json:"system-err,omitempty"`
+}
+
+// NewRerun returns a Rerun with the given message, type, and stack trace
+func NewRerun(msg string, rerunType string, stackTrace string) *Rerun {
+	return &Rerun{
+		Message:    msg,
+		Type:       rerunType,
+		StackTrace: stackTrace,
+	}
+}
+
+// RetryPolicy defines how TestSuite.RunWithRetry retries a test case. It has
+// two fields: MaxAttempts, the maximum amount of times the test is run, which
+// is at least once, and Delay, the time waited between attempts.
+type RetryPolicy struct {
+	MaxAttempts int
+	Delay       time.Duration
+}
+
+// NewRetryPolicy returns a RetryPolicy with the given maximum amount of
+// attempts and no delay
+func NewRetryPolicy(maxAttempts int) RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: maxAttempts,
+	}
+}
+
+// RunWithRetry runs fn like Run, retrying it according to policy while it
+// fails. Every attempt is recorded in the test case:
+// - if an attempt passes, the test case passes and the failures and errors of
+// the previous attempts are added as flaky failures and flaky errors;
+// - if all attempts fail, the failures and errors of the first attempt are
+// added as failures and errors and the ones of the other attempts as rerun
+// failures and rerun errors.
+// A skipped attempt isn't retried. The test case time is the sum of the time
+// of all attempts. The test case is returned after it is added to the suite.
+func (suite *TestSuite) RunWithRetry(name string, classname string, policy RetryPolicy, fn func(testCase *TestCase) error) *TestCase {
+	testCase := NewTestCase("", name, classname)
+	suite.captureLocation(testCase, 1)
+	attempts := []*TestCase{}
+
+	maxAttempts := policy.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	for i := 0; i < maxAttempts; i++ {
+		if i > 0 && policy.Delay > 0 {
+			time.Sleep(policy.Delay)
+		}
+
+		attempt := NewTestCase("", name, classname)
+		attempt.run(fn)
+		attempts = append(attempts, attempt)
+		testCase.Time += attempt.Time
+
+		if attempt.passed() {
+			break
+		}
+	}
+
+	last := attempts[len(attempts)-1]
+	testCase.Content = last.Content
+	testCase.Properties = last.Properties
+	testCase.Skipped = last.Skipped
+	testCase.SystemOut = last.SystemOut
+	testCase.SystemErr = last.SystemErr
+
+	if last.passed() {
+		for _, attempt := range attempts[:len(attempts)-1] {
+			testCase.FlakyFailures = append(testCase.FlakyFailures, attempt.failureReruns()...)
+			testCase.FlakyErrors = append(testCase.FlakyErrors, attempt.errorReruns()...)
+		}
+	} else {
+		testCase.Failures = attempts[0].Failures
+		testCase.Errors = attempts[0].Errors
+		testCase.SystemOut = attempts[0].SystemOut
+		testCase.SystemErr = attempts[0].SystemErr
+
+		for _, attempt := range attempts[1:] {
+			testCase.RerunFailures = append(testCase.RerunFailures, attempt.failureReruns()...)
+			testCase.RerunErrors = append(testCase.RerunErrors, attempt.errorReruns()...)
+		}
+	}
+
+	suite.addAnonymous(testCase)
+	return testCase
+}
+
+// passed reports whether the test case has no failures and no errors. Skipped
+// test cases are considered passed, since they must not be retried.
+func (testCase *TestCase) passed() bool {
+	return len(testCase.Failures) == 0 && len(testCase.Errors) == 0
+}
+
+// failureReruns returns the failures of the test case as reruns with the test
+// case time and output
+func (testCase *TestCase) failureReruns() []*Rerun {
+	reruns := []*Rerun{}
+	for _, f := range testCase.Failures {
+		reruns = append(reruns, testCase.rerun(f.Message, f.Type, f.Content))
+	}
+
+	return reruns
+}
+
+// errorReruns returns the errors of the test case as reruns with the test case
+// time and output
+func (testCase *TestCase) errorReruns() []*Rerun {
+	reruns := []*Rerun{}
+	for _, e := range testCase.Errors {
+		reruns = append(reruns, testCase.rerun(e.Message, e.Type, e.Content))
+	}
+
+	return reruns
+}
+
+func (testCase *TestCase) rerun(msg string, rerunType string, stackTrace string) *Rerun {
+	rerun := NewRerun(msg, rerunType, stackTrace)
+	rerun.Time = testCase.Time
+	rerun.SystemOut = testCase.SystemOut
+	rerun.SystemErr = testCase.SystemErr
+	return rerun
+}
@@ -0,0 +1,148 @@
+package report
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRerun(t *testing.T) {
+	actual := NewRerun("message", "type", "stack trace")
+
+	expected := &Rerun{
+		Message:    "message",
+		Type:       "type",
+		StackTrace: "stack trace",
+	}
+
+	assert.Equal(t, expected, actual)
+}
+
+func TestRunWithRetry_Flaky(t *testing.T) {
+	suite := NewAnonymousTestSuite()
+	attempt := 0
+
+	actual := suite.RunWithRetry("name", "class", NewRetryPolicy(5), func(testCase *TestCase) error {
+		attempt++
+		fmt.Fprintf(testCase.SystemOutWriter(), "attempt %d", attempt)
+
+		switch attempt {
+		case 1:
+			return NewFailure("assertion", "", "failure 1")
+		case 2:
+			return errors.New("connection refused")
+		}
+
+		return nil
+	})
+
+	assert.Equal(t, 3, attempt)
+	assert.Equal(t, []*TestCase{actual}, suite.TestCases)
+	assert.Empty(t, actual.Failures)
+	assert.Empty(t, actual.Errors)
+	assert.Equal(t, "attempt 3", actual.SystemOut)
+
+	assert.Equal(t, 1, len(actual.FlakyFailures))
+	assert.Equal(t, "assertion", actual.FlakyFailures[0].Message)
+	assert.Equal(t, "failure 1", actual.FlakyFailures[0].StackTrace)
+	assert.Equal(t, "attempt 1", actual.FlakyFailures[0].SystemOut)
+	assert.NotZero(t, actual.FlakyFailures[0].Time)
+
+	assert.Equal(t, 1, len(actual.FlakyErrors))
+	assert.Equal(t, "connection refused", actual.FlakyErrors[0].Message)
+	assert.Equal(t, "attempt 2", actual.FlakyErrors[0].SystemOut)
+
+	assert.Empty(t, actual.RerunFailures)
+	assert.Empty(t, actual.RerunErrors)
+	assert.True(t, actual.Time >= actual.FlakyFailures[0].Time+actual.FlakyErrors[0].Time)
+}
+
+func TestRunWithRetry_Failed(t *testing.T) {
+	suite := NewAnonymousTestSuite()
+	attempt := 0
+
+	actual := suite.RunWithRetry("name", "class", NewRetryPolicy(3), func(testCase *TestCase) error {
+		attempt++
+		fmt.Fprintf(testCase.SystemOutWriter(), "attempt %d", attempt)
+
+		if attempt == 2 {
+			panic("boom")
+		}
+
+		return NewFailure(fmt.Sprintf("failure %d", attempt), "", "")
+	})
+
+	assert.Equal(t, 3, attempt)
+	assert.Equal(t, []*Failure{NewFailure("failure 1", "", "")}, actual.Failures)
+	assert.Empty(t, actual.Errors)
+	assert.Equal(t, "attempt 1", actual.SystemOut)
+
+	assert.Equal(t, 1, len(actual.RerunErrors))
+	assert.Equal(t, "boom", actual.RerunErrors[0].Message)
+	assert.Equal(t, "attempt 2", actual.RerunErrors[0].SystemOut)
+
+	assert.Equal(t, 1, len(actual.RerunFailures))
+	assert.Equal(t, "failure 3", actual.RerunFailures[0].Message)
+	assert.Equal(t, "attempt 3", actual.RerunFailures[0].SystemOut)
+
+	assert.Empty(t, actual.FlakyFailures)
+	assert.Empty(t, actual.FlakyErrors)
+}
+
+func TestRunWithRetry_Skipped(t *testing.T) {
+	suite := NewAnonymousTestSuite()
+	attempt := 0
+
+	actual := suite.RunWithRetry("name", "class", RetryPolicy{}, func(testCase *TestCase) error {
+		attempt++
+		testCase.Skip("skip")
+		return nil
+	})
+
+	assert.Equal(t, 1, attempt)
+	assert.Equal(t, "skip", actual.Skipped.Message)
+}
+
+func TestRerun_MakeReport(t *testing.T) {
+	testCase := NewTestCase("", "name", "class")
+	rerun := NewRerun("msg", "type", "stack")
+	rerun.Time = 1500000000
+	rerun.SystemOut = "out"
+	testCase.AddFlakyFailure(rerun)
+	testCase.AddFlakyError(NewRerun("msg", "", ""))
+	testCase.AddRerunFailure(NewRerun("msg", "", ""))
+	testCase.AddRerunError(NewRerun("msg", "", ""))
+
+	suite := NewAnonymousTestSuite()
+	err := suite.AddTestCase(testCase)
+	assert.Nil(t, err)
+	suites := NewAnonymousTestSuites()
+	err = suites.AddTestSuite(suite)
+	assert.Nil(t, err)
+
+	content, err := suites.MakeReport()
+	assert.Nil(t, err)
+
+	expected := strings.Join([]string{
+		`        <testcase name="name" classname="class">`,
+		`            <flakyFailure message="msg" type="type" time="1.500">`,
+		`                <stackTrace>stack</stackTrace>`,
+		`                <system-out>out</system-out>`,
+		`            </flakyFailure>`,
+		`            <flakyError message="msg"></flakyError>`,
+		`            <rerunFailure message="msg"></rerunFailure>`,
+		`            <rerunError message="msg"></rerunError>`,
+		`        </testcase>`,
+	}, "\n")
+	assert.Contains(t, string(content), expected)
+	assert.Equal(t, 0, suites.Failures)
+
+	parsed, err := ParseReport(bytes.NewReader(content))
+	assert.Nil(t, err)
+	assert.Equal(t, []*Rerun{rerun}, parsed.TestSuites[0].TestCases[0].FlakyFailures)
+	assert.Equal(t, 1, len(parsed.TestSuites[0].TestCases[0].RerunErrors))
+}
@@ -0,0 +1,79 @@
+package report
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+)
+
+// failNow is the value TestCase.FailNow panics with. It is recovered by
+// TestSuite.Run.
+type failNow struct{}
+
+// Run runs fn as a test case with the given name and classname and adds it to
+// the suite. The test case is timed, and the outcome of fn is recorded as
+// follows:
+// - a returned *Failure, or an error wrapping one, is added as a failure;
+// - any other returned error is added as an error;
+// - a panic is recovered and added as an error with the stack trace.
+// Inside fn, failures can also be recorded assertion-style with
+// TestCase.Errorf and TestCase.FailNow, so a TestCase can be used with
+// assertion libraries such as testify. The test case is returned after it is
+// added to the suite.
+func (suite *TestSuite) Run(name string, classname string, fn func(testCase *TestCase) error) *TestCase {
+	testCase := NewTestCase("", name, classname)
+	suite.captureLocation(testCase, 1)
+	testCase.run(fn)
+
+	suite.addAnonymous(testCase)
+	return testCase
+}
+
+// run runs fn and records its outcome in the test case
+func (testCase *TestCase) run(fn func(testCase *TestCase) error) {
+	testCase.Start()
+	defer testCase.End()
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+
+		if _, ok := r.(failNow); ok {
+			return
+		}
+
+		testCase.AddError(NewError(
+			fmt.Sprint(r),
+			"panic",
+			string(debug.Stack()),
+		))
+	}()
+
+	err := fn(testCase)
+	if err == nil {
+		return
+	}
+
+	var failure *Failure
+	if errors.As(err, &failure) {
+		testCase.AddFailure(failure)
+		return
+	}
+
+	testCase.AddError(NewError(err.Error(), fmt.Sprintf("%T", err), fmt.Sprintf("%+v", err)))
+}
+
+// Errorf adds a failure with the formatted message to the test case. Together
+// with FailNow, it allows a TestCase to be used as the testing.TB like argument
+// of assertion libraries.
+func (testCase *TestCase) Errorf(format string, args ...interface{}) {
+	testCase.AddFailure(NewAnonymousFailure(fmt.Sprintf(format, args...)))
+}
+
+// FailNow stops the test case. It must only be called from the function given
+// to TestSuite.Run, since it stops it by panicking.
+func (testCase *TestCase) FailNow() {
+	panic(failNow{})
+}
@@ -0,0 +1,88 @@
+package report
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun_Success(t *testing.T) {
+	suite := NewAnonymousTestSuite()
+
+	actual := suite.Run("name", "class", func(testCase *TestCase) error {
+		fmt.Fprint(testCase.SystemOutWriter(), "output")
+		return nil
+	})
+
+	assert.Equal(t, []*TestCase{actual}, suite.TestCases)
+	assert.Equal(t, "name", actual.Name)
+	assert.Equal(t, "class", actual.Classname)
+	assert.Equal(t, "output", actual.SystemOut)
+	assert.Empty(t, actual.Failures)
+	assert.Empty(t, actual.Errors)
+	assert.NotZero(t, actual.Time)
+}
+
+func TestRun_Error(t *testing.T) {
+	suite := NewAnonymousTestSuite()
+
+	actual := suite.Run("name", "class", func(testCase *TestCase) error {
+		return fmt.Errorf("wrapped: %w", errors.New("connection refused"))
+	})
+
+	assert.Equal(t, []*Error{
+		{
+			Message: "wrapped: connection refused",
+			Type:    "*fmt.wrapError",
+			Content: "wrapped: connection refused",
+		},
+	}, actual.Errors)
+	assert.Empty(t, actual.Failures)
+}
+
+func TestRun_Failure(t *testing.T) {
+	suite := NewAnonymousTestSuite()
+
+	actual := suite.Run("name", "class", func(testCase *TestCase) error {
+		return fmt.Errorf("check: %w", NewFailure("msg", "type", "content"))
+	})
+
+	assert.Equal(t, []*Failure{NewFailure("msg", "type", "content")}, actual.Failures)
+	assert.Empty(t, actual.Errors)
+}
+
+func TestRun_Panic(t *testing.T) {
+	suite := NewAnonymousTestSuite()
+
+	actual := suite.Run("name", "class", func(testCase *TestCase) error {
+		panic("boom")
+	})
+
+	assert.Equal(t, 1, len(actual.Errors))
+	assert.Equal(t, "boom", actual.Errors[0].Message)
+	assert.Equal(t, "panic", actual.Errors[0].Type)
+	assert.Contains(t, actual.Errors[0].Content, "TestRun_Panic")
+	assert.NotZero(t, actual.Time)
+	assert.Equal(t, 1, len(suite.TestCases))
+}
+
+func TestRun_Assertions(t *testing.T) {
+	suite := NewAnonymousTestSuite()
+	reached := false
+
+	actual := suite.Run("name", "class", func(testCase *TestCase) error {
+		assert.Equal(testCase, 1, 2)
+		assert.True(testCase, true)
+		require.Equal(testCase, "a", "b")
+		reached = true
+		return nil
+	})
+
+	assert.False(t, reached)
+	assert.Equal(t, 2, len(actual.Failures))
+	assert.Contains(t, actual.Failures[0].Content, "Not equal")
+	assert.Empty(t, actual.Errors)
+}
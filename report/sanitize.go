@@ -0,0 +1,209 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// SanitizeMode defines what a Sanitizer does with characters that are illegal
+// in XML 1.0, such as "\x00", and with invalid UTF-8
+type SanitizeMode int
+
+const (
+	// SanitizeReplace replaces illegal characters with U+FFFD, which is what
+	// encoding/xml does with character data.
+	SanitizeReplace SanitizeMode = iota
+	// SanitizeStrip removes illegal characters.
+	SanitizeStrip
+	// SanitizeEscape replaces illegal characters with a visible escape
+	// sequence, eg: "\x00" becomes the text `\x00`.
+	SanitizeEscape
+)
+
+// ansiPattern matches ANSI escape sequences, such as colors and cursor
+// movements
+var ansiPattern = regexp.MustCompile(`\x1b(\[[0-?]*[ -/]*[@-~]|\][^\x07\x1b]*(\x07|\x1b\\)|[@-Z\\-_])`)
+
+// Sanitizer cleans the text of a report while it is rendered by
+// TestSuites.MakeReport and ReportWriter, so output captured from tests, such
+// as binary protocol dumps, doesn't produce XML that CI tools refuse to parse.
+// It has the following fields:
+// Mode: what to do with illegal characters. Defaults to SanitizeReplace.
+// StripANSI: whether ANSI escape sequences, eg: terminal colors, are removed
+// before illegal characters are handled.
+// CDATAMinLength: failure and error contents with at least this many bytes,
+// after being sanitized, are written as CDATA sections. Zero disables CDATA.
+// The report itself is never changed.
+type Sanitizer struct {
+	Mode           SanitizeMode
+	StripANSI      bool
+	CDATAMinLength int
+}
+
+// NewSanitizer returns a Sanitizer with the given mode
+func NewSanitizer(mode SanitizeMode) *Sanitizer {
+	return &Sanitizer{
+		Mode: mode,
+	}
+}
+
+// Sanitize returns s without ANSI escape sequences, if StripANSI is set, and
+// with illegal characters handled according to Mode
+func (sanitizer *Sanitizer) Sanitize(s string) string {
+	if sanitizer.StripANSI {
+		s = ansiPattern.ReplaceAllString(s, "")
+	}
+
+	if isLegalXMLText(s) {
+		return s
+	}
+
+	b := strings.Builder{}
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		invalid := r == utf8.RuneError && size == 1
+
+		switch {
+		case !invalid && isLegalXMLChar(r):
+			b.WriteRune(r)
+		case sanitizer.Mode == SanitizeStrip:
+		case sanitizer.Mode == SanitizeEscape && (invalid || r < 0x100):
+			fmt.Fprintf(&b, `\x%02X`, s[i])
+		case sanitizer.Mode == SanitizeEscape:
+			fmt.Fprintf(&b, `\u%04X`, r)
+		default:
+			b.WriteRune(utf8.RuneError)
+		}
+
+		i += size
+	}
+
+	return b.String()
+}
+
+// isLegalXMLText reports whether s is valid UTF-8 without illegal characters
+func isLegalXMLText(s string) bool {
+	if !utf8.ValidString(s) {
+		return false
+	}
+
+	for _, r := range s {
+		if !isLegalXMLChar(r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// testSuites sanitizes a copy of the report in place, see Snapshot. Test
+// suites are sanitized with testSuite.
+func (sanitizer *Sanitizer) testSuites(suites *TestSuites) {
+	suites.ID = sanitizer.Sanitize(suites.ID)
+	suites.Name = sanitizer.Sanitize(suites.Name)
+	suites.Hostname = sanitizer.Sanitize(suites.Hostname)
+	sanitizer.properties(suites.Properties)
+	for _, suite := range suites.TestSuites {
+		sanitizer.testSuite(suite)
+	}
+}
+
+// testSuite sanitizes a copy of a suite in place. Test cases are sanitized with
+// testCase and nested suites with testSuite.
+func (sanitizer *Sanitizer) testSuite(suite *TestSuite) {
+	suite.ID = sanitizer.Sanitize(suite.ID)
+	suite.Name = sanitizer.Sanitize(suite.Name)
+	suite.Package = sanitizer.Sanitize(suite.Package)
+	suite.Hostname = sanitizer.Sanitize(suite.Hostname)
+	suite.SystemOut = sanitizer.Sanitize(suite.SystemOut)
+	suite.SystemErr = sanitizer.Sanitize(suite.SystemErr)
+	sanitizer.properties(suite.Properties)
+	for _, testCase := range suite.TestCases {
+		sanitizer.testCase(testCase)
+	}
+
+	for _, nested := range suite.TestSuites {
+		sanitizer.testSuite(nested)
+	}
+}
+
+// testCase sanitizes a copy of a test case in place
+func (sanitizer *Sanitizer) testCase(testCase *TestCase) {
+	testCase.ID = sanitizer.Sanitize(testCase.ID)
+	testCase.Name = sanitizer.Sanitize(testCase.Name)
+	testCase.Classname = sanitizer.Sanitize(testCase.Classname)
+	testCase.File = sanitizer.Sanitize(testCase.File)
+	testCase.Content = sanitizer.Sanitize(testCase.Content)
+	testCase.SystemOut = sanitizer.Sanitize(testCase.SystemOut)
+	testCase.SystemErr = sanitizer.Sanitize(testCase.SystemErr)
+	sanitizer.properties(testCase.Properties)
+
+	if testCase.Skipped != nil {
+		testCase.Skipped.Message = sanitizer.Sanitize(testCase.Skipped.Message)
+		testCase.Skipped.Content = sanitizer.Sanitize(testCase.Skipped.Content)
+	}
+
+	for _, f := range testCase.Failures {
+		f.Message = sanitizer.Sanitize(f.Message)
+		f.Type = sanitizer.Sanitize(f.Type)
+		f.File = sanitizer.Sanitize(f.File)
+		f.Content = sanitizer.Sanitize(f.Content)
+		f.cdata = sanitizer.useCDATA(f.Content)
+	}
+
+	for _, e := range testCase.Errors {
+		e.Message = sanitizer.Sanitize(e.Message)
+		e.Type = sanitizer.Sanitize(e.Type)
+		e.File = sanitizer.Sanitize(e.File)
+		e.Content = sanitizer.Sanitize(e.Content)
+		e.cdata = sanitizer.useCDATA(e.Content)
+	}
+
+	sanitizer.reruns(testCase.FlakyFailures)
+	sanitizer.reruns(testCase.FlakyErrors)
+	sanitizer.reruns(testCase.RerunFailures)
+	sanitizer.reruns(testCase.RerunErrors)
+}
+
+func (sanitizer *Sanitizer) reruns(reruns []*Rerun) {
+	for _, r := range reruns {
+		r.Message = sanitizer.Sanitize(r.Message)
+		r.Type = sanitizer.Sanitize(r.Type)
+		r.StackTrace = sanitizer.Sanitize(r.StackTrace)
+		r.SystemOut = sanitizer.Sanitize(r.SystemOut)
+		r.SystemErr = sanitizer.Sanitize(r.SystemErr)
+	}
+}
+
+func (sanitizer *Sanitizer) properties(properties *Properties) {
+	if properties == nil {
+		return
+	}
+
+	for _, p := range properties.Properties {
+		p.Name = sanitizer.Sanitize(p.Name)
+		p.Value = sanitizer.Sanitize(p.Value)
+	}
+}
+
+func (sanitizer *Sanitizer) useCDATA(content string) bool {
+	return sanitizer.CDATAMinLength > 0 && len(content) >= sanitizer.CDATAMinLength
+}
+
+// cdataElement is used to write failures and errors with their content as a
+// CDATA section
+type cdataElement struct {
+	Message string `xml:"message,attr,omitempty"`
+	Type    string `xml:"type,attr,omitempty"`
+	File    string `xml:"file,attr,omitempty"`
+	Line    int    `xml:"line,attr,omitempty"`
+	Content string `xml:",cdata"`
+}
+
+// encodeCDATA writes a failure or error with its content as a CDATA section
+func encodeCDATA(e *xml.Encoder, start xml.StartElement, element cdataElement) error {
+	return e.EncodeElement(element, start)
+}
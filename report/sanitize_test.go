@@ -0,0 +1,117 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitize(t *testing.T) {
+	input := "a\x00b\x1b[31mred\x1b[0m\xffc￾d\t\n"
+
+	tests := []struct {
+		sanitizer *Sanitizer
+		expected  string
+	}{
+		{
+			sanitizer: NewSanitizer(SanitizeReplace),
+			expected:  "a�b�[31mred�[0m�c�d\t\n",
+		},
+		{
+			sanitizer: NewSanitizer(SanitizeStrip),
+			expected:  "ab[31mred[0mcd\t\n",
+		},
+		{
+			sanitizer: NewSanitizer(SanitizeEscape),
+			expected:  `a\x00b\x1B[31mred\x1B[0m\xFFc\uFFFEd` + "\t\n",
+		},
+		{
+			sanitizer: &Sanitizer{Mode: SanitizeStrip, StripANSI: true},
+			expected:  "abredcd\t\n",
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, test.sanitizer.Sanitize(input))
+	}
+}
+
+func TestSanitize_Legal(t *testing.T) {
+	input := "já passou \U0001F600 <tag> & \"quotes\""
+
+	assert.Equal(t, input, NewSanitizer(SanitizeStrip).Sanitize(input))
+}
+
+func TestSanitize_ANSI(t *testing.T) {
+	sanitizer := &Sanitizer{StripANSI: true}
+
+	assert.Equal(t, "bold link", sanitizer.Sanitize("\x1b[1;31mbold\x1b[K \x1b]8;;http://x\x07link\x1b]8;;\x07"))
+}
+
+func TestMakeReport_Sanitizer(t *testing.T) {
+	suites := NewTestSuites("id", "name\x00")
+	suites.Sanitizer = &Sanitizer{
+		Mode:           SanitizeEscape,
+		StripANSI:      true,
+		CDATAMinLength: 10,
+	}
+
+	suite := NewTestSuite("suite", "suite")
+	testCase := NewTestCase("case", "case", "class")
+	testCase.AddFailure(NewFailure("short", "", "\x1b[31mshort\x1b[0m"))
+	testCase.AddFailure(NewFailure("long", "", "long <content> with ]]> and \x00"))
+	testCase.AddError(NewError("long", "", "error content with & and \x01"))
+	testCase.SystemOut = "binary \x02"
+	err := suite.AddTestCase(testCase)
+	assert.Nil(t, err)
+	err = suites.AddTestSuite(suite)
+	assert.Nil(t, err)
+
+	content, err := suites.MakeReport()
+	assert.Nil(t, err)
+
+	actual := string(content)
+	assert.Contains(t, actual, `name="name\x00"`)
+	assert.Contains(t, actual, `<failure message="short">short</failure>`)
+	assert.Contains(t, actual, `<failure message="long"><![CDATA[long <content> with ]]]]><![CDATA[> and \x00]]></failure>`)
+	assert.Contains(t, actual, `<error message="long"><![CDATA[error content with & and \x01]]></error>`)
+	assert.Contains(t, actual, `<system-out>binary \x02</system-out>`)
+
+	assert.Equal(t, "name\x00", suites.Name)
+	assert.Equal(t, "\x1b[31mshort\x1b[0m", testCase.Failures[0].Content)
+	assert.False(t, testCase.Failures[1].cdata)
+
+	parsed, err := ParseReport(bytes.NewReader(content))
+	assert.Nil(t, err)
+	assert.Equal(t, `long <content> with ]]> and \x00`, parsed.TestSuites[0].TestCases[0].Failures[1].Content)
+}
+
+func TestReportWriter_Sanitizer(t *testing.T) {
+	header := NewTestSuites("id", "name")
+	header.Sanitizer = NewSanitizer(SanitizeStrip)
+	buffer := &bytes.Buffer{}
+
+	rw, err := NewReportWriter(buffer, header)
+	assert.Nil(t, err)
+
+	suite := NewTestSuite("suite\x00", "suite")
+	suite.SystemOut = "out\x00"
+	err = rw.StartSuite(suite)
+	assert.Nil(t, err)
+
+	testCase := NewTestCase("case", "case", "class")
+	testCase.AddError(NewError("msg\x00", "", "content\x00"))
+	err = rw.WriteTestCase(testCase)
+	assert.Nil(t, err)
+
+	err = rw.Close()
+	assert.Nil(t, err)
+
+	assert.False(t, strings.ContainsRune(buffer.String(), 0))
+	assert.NotContains(t, buffer.String(), "�")
+
+	_, err = ParseReport(buffer)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,143 @@
+package report
+
+import (
+	"encoding/json"
+	"path/filepath"
+)
+
+// SARIFVersion is the version of the SARIF documents written by SARIFFormatter
+const SARIFVersion = "2.1.0"
+
+// sarifSchema is the JSON schema of SARIF 2.1.0 documents
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+// SARIFFormatter renders the failures and errors of the test cases as a SARIF
+// 2.1.0 document with a single run, so code analysis tools can show them
+// inline. Each test case is a rule, named after its suite and its own name,
+// with nested suites flattened and their names joined by " / ". The file and
+// line of a failure or error, or of its test case if it has none, are its
+// location. Passed and skipped test cases aren't included. ToolName is the
+// name of the tool that produced the results, defaults to the report name.
+type SARIFFormatter struct {
+	ToolName string
+}
+
+// Format implements Formatter
+func (f SARIFFormatter) Format(suites *TestSuites) ([]byte, error) {
+	summary := suites.Snapshot().summary()
+
+	toolName := f.ToolName
+	if len(toolName) == 0 {
+		toolName = summary.title()
+	}
+
+	run := &sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:  toolName,
+				Rules: []*sarifRule{},
+			},
+		},
+		Results: []*sarifResult{},
+	}
+
+	rules := map[string]int{}
+	for _, finding := range summary.findings() {
+		index, ok := rules[finding.check]
+		if !ok {
+			index = len(run.Tool.Driver.Rules)
+			rules[finding.check] = index
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, &sarifRule{ID: finding.check})
+		}
+
+		result := &sarifResult{
+			RuleID:    finding.check,
+			RuleIndex: index,
+			Level:     "error",
+			Message:   sarifMessage{Text: finding.message},
+		}
+		if len(finding.problemType) > 0 {
+			result.Properties = &sarifProperties{Type: finding.problemType}
+		}
+		if len(finding.file) > 0 {
+			location := &sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(finding.file)},
+			}
+			if finding.line > 0 {
+				location.Region = &sarifRegion{StartLine: finding.line}
+			}
+			result.Locations = []*sarifLocation{{PhysicalLocation: location}}
+		}
+
+		run.Results = append(run.Results, result)
+	}
+
+	content, err := json.MarshalIndent(&sarifLog{
+		Schema:  sarifSchema,
+		Version: SARIFVersion,
+		Runs:    []*sarifRun{run},
+	}, "", "  ")
+	if err != nil {
+		return []byte{}, err
+	}
+
+	return append(content, '\n'), nil
+}
+
+type sarifLog struct {
+	Schema  string      `json:"$schema"`
+	Version string      `json:"version"`
+	Runs    []*sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool      `json:"tool"`
+	Results []*sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string       `json:"name"`
+	Rules []*sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifResult struct {
+	RuleID     string           `json:"ruleId"`
+	RuleIndex  int              `json:"ruleIndex"`
+	Level      string           `json:"level"`
+	Message    sarifMessage     `json:"message"`
+	Locations  []*sarifLocation `json:"locations,omitempty"`
+	Properties *sarifProperties `json:"properties,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+type sarifProperties struct {
+	Type string `json:"type"`
+}
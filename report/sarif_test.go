@@ -0,0 +1,92 @@
+package report
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSARIFFormatter(t *testing.T) {
+	suites, err := LoadReport(filepath.Join("testdata", "findings.xml"))
+	assert.Nil(t, err)
+
+	content, err := SARIF.Format(suites)
+	assert.Nil(t, err)
+
+	expected := `{
+  "$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+  "version": "2.1.0",
+  "runs": [
+    {
+      "tool": {
+        "driver": {
+          "name": "lint",
+          "rules": [
+            {
+              "id": "contracts / unused field"
+            },
+            {
+              "id": "contracts / naming"
+            }
+          ]
+        }
+      },
+      "results": [
+        {
+          "ruleId": "contracts / unused field",
+          "ruleIndex": 0,
+          "level": "error",
+          "message": {
+            "text": "field id is never read"
+          },
+          "locations": [
+            {
+              "physicalLocation": {
+                "artifactLocation": {
+                  "uri": "api/user.proto"
+                },
+                "region": {
+                  "startLine": 12
+                }
+              }
+            }
+          ],
+          "properties": {
+            "type": "unused"
+          }
+        },
+        {
+          "ruleId": "contracts / naming",
+          "ruleIndex": 1,
+          "level": "error",
+          "message": {
+            "text": "bad name\nin package"
+          }
+        },
+        {
+          "ruleId": "contracts / naming",
+          "ruleIndex": 1,
+          "level": "error",
+          "message": {
+            "text": "test case errored"
+          }
+        }
+      ]
+    }
+  ]
+}
+`
+	assert.Equal(t, expected, string(content))
+}
+
+func TestSARIFFormatter_Empty(t *testing.T) {
+	content, err := SARIFFormatter{ToolName: "tool"}.Format(NewAnonymousTestSuites())
+	assert.Nil(t, err)
+
+	decoded := &sarifLog{}
+	assert.Nil(t, json.Unmarshal(content, decoded))
+	assert.Equal(t, "tool", decoded.Runs[0].Tool.Driver.Name)
+	assert.Equal(t, 0, len(decoded.Runs[0].Results))
+}
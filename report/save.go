@@ -0,0 +1,155 @@
+package report
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// saveOptions holds the settings used by TestSuites.SaveReport
+type saveOptions struct {
+	mode       os.FileMode
+	atomic     bool
+	gzip       bool
+	createDirs bool
+	formatter  Formatter
+}
+
+// SaveOption changes how TestSuites.SaveReport writes the report file
+type SaveOption func(*saveOptions)
+
+// WithFileMode sets the permission bits of the report file. Defaults to 0644.
+func WithFileMode(mode os.FileMode) SaveOption {
+	return func(o *saveOptions) {
+		o.mode = mode
+	}
+}
+
+// WithAtomicWrite makes the report be written to a temporary file in the same
+// directory, which is then renamed to the report file name. The report file is
+// never left truncated if the process is killed while writing it.
+func WithAtomicWrite() SaveOption {
+	return func(o *saveOptions) {
+		o.atomic = true
+	}
+}
+
+// WithGzip makes the report be compressed with gzip. The file name isn't
+// changed, so it should usually end with ".gz".
+func WithGzip() SaveOption {
+	return func(o *saveOptions) {
+		o.gzip = true
+	}
+}
+
+// WithParentDirs makes the parent directories of the report file be created
+// with the 0755 permission settings if they don't exist.
+func WithParentDirs() SaveOption {
+	return func(o *saveOptions) {
+		o.createDirs = true
+	}
+}
+
+// WithFormatter sets the format of the report file, overriding the one chosen
+// by the file extension
+func WithFormatter(formatter Formatter) SaveOption {
+	return func(o *saveOptions) {
+		o.formatter = formatter
+	}
+}
+
+// WriteTo writes the report XML to w. It implements io.WriterTo. All values are
+// automatically calculated when calling this method.
+func (suites *TestSuites) WriteTo(w io.Writer) (int64, error) {
+	content, err := suites.MakeReport()
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := w.Write(content)
+	return int64(n), err
+}
+
+// newSaveOptions returns the default options changed by opts
+func newSaveOptions(opts []SaveOption) *saveOptions {
+	options := &saveOptions{
+		mode: 0644,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	return options
+}
+
+// writeFile writes content to filename according to the given options
+func writeFile(filename string, content []byte, options *saveOptions) error {
+	if options.gzip {
+		compressed, err := gzipContent(content)
+		if err != nil {
+			return err
+		}
+		content = compressed
+	}
+
+	if options.createDirs {
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			return err
+		}
+	}
+
+	if !options.atomic {
+		return os.WriteFile(filename, content, options.mode)
+	}
+
+	return writeFileAtomic(filename, content, options.mode)
+}
+
+// writeFileAtomic writes content to a temporary file and renames it to
+// filename
+func writeFileAtomic(filename string, content []byte, mode os.FileMode) error {
+	f, err := os.CreateTemp(filepath.Dir(filename), "."+filepath.Base(filename)+".tmp*")
+	if err != nil {
+		return err
+	}
+
+	tmp := f.Name()
+	defer os.Remove(tmp)
+
+	if _, err := f.Write(content); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Chmod(tmp, mode); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, filename)
+}
+
+func gzipContent(content []byte) ([]byte, error) {
+	buffer := &bytes.Buffer{}
+	w := gzip.NewWriter(buffer)
+
+	if _, err := w.Write(content); err != nil {
+		return nil, err
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
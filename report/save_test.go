@@ -0,0 +1,109 @@
+package report
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteTo(t *testing.T) {
+	suites := newTestSuites(t, "id", "id")
+	expected, err := suites.MakeReport()
+	assert.Nil(t, err)
+
+	buffer := &bytes.Buffer{}
+	n, err := suites.WriteTo(buffer)
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(len(expected)), n)
+	assert.Equal(t, string(expected), buffer.String())
+}
+
+func TestSaveReport(t *testing.T) {
+	suites := newTestSuites(t, "id", "id")
+	expected, err := suites.MakeReport()
+	assert.Nil(t, err)
+
+	filename := filepath.Join(t.TempDir(), "report.xml")
+	err = suites.SaveReport(filename)
+	assert.Nil(t, err)
+
+	actual, err := os.ReadFile(filename)
+	assert.Nil(t, err)
+	assert.Equal(t, string(expected), string(actual))
+
+	info, err := os.Stat(filename)
+	assert.Nil(t, err)
+	assert.Zero(t, info.Mode().Perm()&^0644)
+}
+
+func TestSaveReport_Options(t *testing.T) {
+	suites := newTestSuites(t, "id", "id")
+	expected, err := suites.MakeReport()
+	assert.Nil(t, err)
+
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "nested", "dir", "report.xml.gz")
+	err = suites.SaveReport(
+		filename,
+		WithParentDirs(),
+		WithAtomicWrite(),
+		WithGzip(),
+		WithFileMode(0600),
+	)
+	assert.Nil(t, err)
+
+	f, err := os.Open(filename)
+	assert.Nil(t, err)
+	defer f.Close()
+
+	r, err := gzip.NewReader(f)
+	assert.Nil(t, err)
+	actual, err := io.ReadAll(r)
+	assert.Nil(t, err)
+	assert.Equal(t, string(expected), string(actual))
+
+	info, err := os.Stat(filename)
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	entries, err := os.ReadDir(filepath.Join(dir, "nested", "dir"))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(entries))
+}
+
+func TestSaveReport_Error(t *testing.T) {
+	suites := newTestSuites(t, "id", "id")
+	filename := filepath.Join(t.TempDir(), "missing", "report.xml")
+
+	assert.NotNil(t, suites.SaveReport(filename))
+	assert.NotNil(t, suites.SaveReport(filename, WithAtomicWrite()))
+}
+
+func TestSaveReport_Formatter(t *testing.T) {
+	suites := newTestSuites(t, "id", "id")
+	dir := t.TempDir()
+
+	expected, err := JSON.Format(suites)
+	assert.Nil(t, err)
+
+	filename := filepath.Join(dir, "report.json")
+	assert.Nil(t, suites.SaveReport(filename))
+	actual, err := os.ReadFile(filename)
+	assert.Nil(t, err)
+	assert.Equal(t, string(expected), string(actual))
+
+	expected, err = CompactXML.Format(suites)
+	assert.Nil(t, err)
+
+	filename = filepath.Join(dir, "report.json")
+	assert.Nil(t, suites.SaveReport(filename, WithFormatter(CompactXML)))
+	actual, err = os.ReadFile(filename)
+	assert.Nil(t, err)
+	assert.Equal(t, string(expected), string(actual))
+}
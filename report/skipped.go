@@ -0,0 +1,19 @@
+package report
+
+// Skipped corresponds to a skipped tag inside testcase and should be added when
+// a test case is intentionally not run. A test case can be skipped only once.
+// It has two fields: Message and Content. Message maps to the message optional
+// attribute, which can take the reason why the test was skipped, and Content
+// maps to the tag's content text, which can be a detailed explanation.
+type Skipped struct {
+	Message string `xml:"message,attr,omitempty" json:"message,omitempty"`
+	Content string `xml:",chardata" json:"content,omitempty"`
+}
+
+// NewSkipped returns a Skipped with the given message and content
+func NewSkipped(msg string, content string) *Skipped {
+	return &Skipped{
+		Message: msg,
+		Content: content,
+	}
+}
@@ -0,0 +1,18 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSkipped(t *testing.T) {
+	actual := NewSkipped("message", "content")
+
+	expected := &Skipped{
+		Message: "message",
+		Content: "content",
+	}
+
+	assert.Equal(t, expected, actual)
+}
@@ -0,0 +1,154 @@
+package report
+
+// Snapshot calculates all values of the report and returns a copy of it. The
+// copy shares nothing with the report, so it can be read without any lock
+// while the report is still being built from other goroutines. Formatters that
+// aren't part of this package should render a snapshot instead of the report
+// they are given.
+func (suites *TestSuites) Snapshot() *TestSuites {
+	defer suites.lockAll()()
+
+	suites.resolve()
+	return suites.copy()
+}
+
+// copy returns a deep copy of the report. The locks must be held.
+func (suites *TestSuites) copy() *TestSuites {
+	copied := &TestSuites{
+		XMLName:    suites.XMLName,
+		ID:         suites.ID,
+		Name:       suites.Name,
+		Timestamp:  suites.Timestamp,
+		Hostname:   suites.Hostname,
+		Tests:      suites.Tests,
+		Failures:   suites.Failures,
+		Errors:     suites.Errors,
+		Skipped:    suites.Skipped,
+		Time:       suites.Time,
+		Properties: suites.Properties.copy(),
+		CountMode:  suites.CountMode,
+		Sanitizer:  suites.Sanitizer,
+		WallTime:   suites.WallTime,
+		Precision:  suites.Precision,
+		startTime:  suites.startTime,
+	}
+	if suites.TestSuites != nil {
+		copied.TestSuites = make([]*TestSuite, len(suites.TestSuites))
+		for i, suite := range suites.TestSuites {
+			copied.TestSuites[i] = suite.copy()
+		}
+	}
+
+	return copied
+}
+
+// copy returns a deep copy of the suite. The locks must be held.
+func (suite *TestSuite) copy() *TestSuite {
+	copied := &TestSuite{
+		ID:              suite.ID,
+		Name:            suite.Name,
+		Package:         suite.Package,
+		Timestamp:       suite.Timestamp,
+		Hostname:        suite.Hostname,
+		Time:            suite.Time,
+		Tests:           suite.Tests,
+		Failures:        suite.Failures,
+		Errors:          suite.Errors,
+		Skipped:         suite.Skipped,
+		Properties:      suite.Properties.copy(),
+		SystemOut:       suite.SystemOut,
+		SystemErr:       suite.SystemErr,
+		WallTime:        suite.WallTime,
+		HookCaseNamer:   suite.HookCaseNamer,
+		CaptureLocation: suite.CaptureLocation,
+		LocationRoot:    suite.LocationRoot,
+		startTime:       suite.startTime,
+	}
+	if suite.TestCases != nil {
+		copied.TestCases = make([]*TestCase, len(suite.TestCases))
+		for i, testCase := range suite.TestCases {
+			copied.TestCases[i] = testCase.copy()
+		}
+	}
+
+	if suite.TestSuites != nil {
+		copied.TestSuites = make([]*TestSuite, len(suite.TestSuites))
+		for i, nested := range suite.TestSuites {
+			copied.TestSuites[i] = nested.copy()
+		}
+	}
+
+	return copied
+}
+
+// copy returns a deep copy of the test case. Its lock must be held.
+func (testCase *TestCase) copy() *TestCase {
+	copied := &TestCase{
+		ID:            testCase.ID,
+		Name:          testCase.Name,
+		Time:          testCase.Time,
+		Classname:     testCase.Classname,
+		File:          testCase.File,
+		Line:          testCase.Line,
+		Content:       testCase.Content,
+		Properties:    testCase.Properties.copy(),
+		FlakyFailures: copyReruns(testCase.FlakyFailures),
+		FlakyErrors:   copyReruns(testCase.FlakyErrors),
+		RerunFailures: copyReruns(testCase.RerunFailures),
+		RerunErrors:   copyReruns(testCase.RerunErrors),
+		SystemOut:     testCase.SystemOut,
+		SystemErr:     testCase.SystemErr,
+		startTime:     testCase.startTime,
+	}
+	if testCase.Skipped != nil {
+		skipped := *testCase.Skipped
+		copied.Skipped = &skipped
+	}
+
+	if testCase.Failures != nil {
+		copied.Failures = make([]*Failure, len(testCase.Failures))
+		for i, f := range testCase.Failures {
+			failure := *f
+			copied.Failures[i] = &failure
+		}
+	}
+
+	if testCase.Errors != nil {
+		copied.Errors = make([]*Error, len(testCase.Errors))
+		for i, e := range testCase.Errors {
+			err := *e
+			copied.Errors[i] = &err
+		}
+	}
+
+	return copied
+}
+
+// copy returns a deep copy of the properties, or nil if properties is nil
+func (properties *Properties) copy() *Properties {
+	if properties == nil {
+		return nil
+	}
+
+	copied := NewProperties()
+	for _, p := range properties.Properties {
+		copied.Properties = append(copied.Properties, NewProperty(p.Name, p.Value))
+	}
+
+	return copied
+}
+
+// copyReruns returns a deep copy of reruns
+func copyReruns(reruns []*Rerun) []*Rerun {
+	if reruns == nil {
+		return nil
+	}
+
+	copied := make([]*Rerun, len(reruns))
+	for i, r := range reruns {
+		rerun := *r
+		copied[i] = &rerun
+	}
+
+	return copied
+}
@@ -0,0 +1,39 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshot(t *testing.T) {
+	testCase := NewTestCase("case", "name", "class")
+	testCase.Time = Duration(time.Second)
+	testCase.AddFailure(NewAnonymousFailure("failure"))
+	testCase.AddFlakyError(NewRerun("flaky", "type", "trace"))
+	testCase.SetProperty("attempt", "1")
+	suite := NewTestSuite("suite", "suite")
+	assert.Nil(t, suite.AddTestCase(testCase))
+	suites := NewTestSuites("id", "name")
+	assert.Nil(t, suites.AddTestSuite(suite))
+
+	snapshot := suites.Snapshot()
+
+	assert.Equal(t, suites, snapshot)
+	assert.Equal(t, 1, suites.Tests)
+	assert.Equal(t, 1, suites.Failures)
+	assert.Equal(t, Duration(time.Second), suites.Time)
+
+	copied := snapshot.TestSuites[0].TestCases[0]
+	assert.NotSame(t, testCase, copied)
+	assert.NotSame(t, testCase.Failures[0], copied.Failures[0])
+	assert.NotSame(t, testCase.FlakyErrors[0], copied.FlakyErrors[0])
+	assert.NotSame(t, testCase.Properties, copied.Properties)
+
+	testCase.SetProperty("attempt", "2")
+	testCase.AddError(NewAnonymousError("error"))
+	value, _ := copied.GetProperty("attempt")
+	assert.Equal(t, "1", value)
+	assert.Empty(t, copied.Errors)
+}
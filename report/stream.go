@@ -0,0 +1,469 @@
+package report
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+)
+
+// ReportWriter writes a report incrementally, so the memory used doesn't
+// depend on the amount of test cases. Test cases are written as soon as they
+// are added and aren't kept in memory.
+//
+// Since counters are only known after all test cases of a suite are written,
+// space is reserved for them in the testsuites and testsuite tags and they are
+// back-patched when the suite ends and when the writer is closed. This requires
+// seeking, so if the underlying writer can't seek (eg: stdout, a pipe, or a
+// gzip writer) or only appends (eg: a file opened with os.O_APPEND), the report
+// is written to a temporary file instead and copied to the underlying writer
+// on Close.
+type ReportWriter struct {
+	out       *bufio.Writer
+	sanitizer *Sanitizer
+	seeker    io.WriteSeeker
+	target    io.Writer
+	spool     *os.File
+	offset    int64
+	width     int
+	suites    *TestSuites
+	suite     *TestSuite
+	patchAt   int64
+	rootAt    int64
+	buffer    bytes.Buffer
+	closed    bool
+	rootOpen  bool
+}
+
+// NewReportWriter returns a ReportWriter that writes to w. The ID, name, and
+// properties of suites are written in the testsuites tag, and its counters are
+// set as test cases are written. The test suites already in suites are
+// neither written nor changed. If suites has a Sanitizer, it is used for
+// everything written. If the counters can't be back-patched in w, eg: it can't
+// seek or it is a file opened with os.O_APPEND, a temporary file is created to
+// hold the report until Close is called.
+func NewReportWriter(w io.Writer, suites *TestSuites) (*ReportWriter, error) {
+	rw := &ReportWriter{
+		suites:    suites,
+		sanitizer: suites.Sanitizer,
+		width:     maxCountersWidth(suites.precision()),
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return nil, err
+	}
+
+	if offset, ok := patchOffset(w); ok {
+		rw.seeker = w.(io.WriteSeeker)
+		rw.offset = offset
+	} else {
+		spool, err := os.CreateTemp("", "junit-report-*.xml")
+		if err != nil {
+			return nil, fmt.Errorf("cannot create temporary report file: %w", err)
+		}
+		rw.seeker = spool
+		rw.spool = spool
+		rw.target = w
+	}
+	rw.out = bufio.NewWriter(rw.seeker)
+
+	suites.Tests = 0
+	suites.Failures = 0
+	suites.Errors = 0
+	suites.Skipped = 0
+	suites.Time = 0
+
+	rootAt, err := rw.writeStartTag(
+		"testsuites",
+		"",
+		rw.attr("id", suites.ID),
+		rw.attr("name", suites.Name),
+		rw.attr("timestamp", suites.Timestamp.String()),
+		rw.attr("hostname", suites.Hostname),
+	)
+	if err != nil {
+		rw.removeSpool()
+		return nil, err
+	}
+	rw.rootAt = rootAt
+	rw.rootOpen = true
+
+	if suites.Properties != nil {
+		if err := rw.encode(rw.sanitizeProperties(suites.Properties), "properties", 1); err != nil {
+			rw.removeSpool()
+			return nil, err
+		}
+	}
+
+	return rw, nil
+}
+
+// StartSuite writes the testsuite tag and the properties of suite. Test cases
+// written afterwards belong to it until EndSuite is called, but aren't added to
+// its TestCases. The test cases already in the suite are written immediately
+// and are left in it. If there is a suite that wasn't ended, it is ended first.
+func (rw *ReportWriter) StartSuite(suite *TestSuite) error {
+	if rw.closed {
+		return errors.New("cannot start suite: report writer is closed")
+	}
+
+	if rw.suite != nil {
+		if err := rw.EndSuite(); err != nil {
+			return err
+		}
+	}
+
+	if suite.Timestamp.IsZero() {
+		suite.Timestamp = suite.firstStart()
+	}
+
+	suite.Tests = 0
+	suite.Failures = 0
+	suite.Errors = 0
+	suite.Skipped = 0
+	suite.Time = 0
+
+	patchAt, err := rw.writeStartTag(
+		"testsuite",
+		"    ",
+		rw.attr("id", suite.ID),
+		rw.attr("name", suite.Name),
+		rw.attr("package", suite.Package),
+		rw.attr("timestamp", suite.Timestamp.String()),
+		rw.attr("hostname", suite.Hostname),
+	)
+	if err != nil {
+		return err
+	}
+	rw.patchAt = patchAt
+	rw.suite = suite
+
+	if suite.Properties != nil {
+		if err := rw.encode(rw.sanitizeProperties(suite.Properties), "properties", 2); err != nil {
+			return err
+		}
+	}
+
+	for _, testCase := range suite.TestCases {
+		if err := rw.WriteTestCase(testCase); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WriteTestCase writes a test case in the current suite
+func (rw *ReportWriter) WriteTestCase(testCase *TestCase) error {
+	if rw.suite == nil {
+		return errors.New("cannot write test case: no suite was started")
+	}
+
+	rw.suite.mu.Lock()
+	testCase.mu.RLock()
+	rw.suite.count(testCase, rw.suites.CountMode)
+	snapshot := testCase.copy()
+	testCase.mu.RUnlock()
+	rw.suite.mu.Unlock()
+
+	if rw.sanitizer != nil {
+		rw.sanitizer.testCase(snapshot)
+	}
+
+	return rw.encode(snapshot.render(rw.suites.precision()), "testcase", 2)
+}
+
+// EndSuite writes the nested suites, system-out, and system-err of the current
+// suite and closes its tag. Nested suites are written as a whole, so they are
+// expected to be small. The suite counters are back-patched if possible.
+func (rw *ReportWriter) EndSuite() error {
+	if rw.suite == nil {
+		return errors.New("cannot end suite: no suite was started")
+	}
+
+	suite := rw.suite
+	rw.suite = nil
+
+	if err := rw.writeNestedSuites(suite); err != nil {
+		return err
+	}
+
+	if len(suite.SystemOut) > 0 {
+		if err := rw.encode(rw.sanitize(suite.SystemOut), "system-out", 2); err != nil {
+			return err
+		}
+	}
+
+	if len(suite.SystemErr) > 0 {
+		if err := rw.encode(rw.sanitize(suite.SystemErr), "system-err", 2); err != nil {
+			return err
+		}
+	}
+
+	if err := rw.writeString("\n    </testsuite>"); err != nil {
+		return err
+	}
+
+	suite.resolveTime()
+	rw.suites.count(suite)
+	return rw.patch(rw.patchAt, suite.Tests, suite.Failures, suite.Errors, suite.Skipped, suite.Time)
+}
+
+// writeNestedSuites writes the nested suites of suite and adds their counters
+// to it
+func (rw *ReportWriter) writeNestedSuites(suite *TestSuite) error {
+	unlock := suite.lockAll()
+	snapshots := []*TestSuite{}
+	for _, nested := range suite.TestSuites {
+		nested.resolve(rw.suites.CountMode)
+		suite.add(nested)
+		snapshots = append(snapshots, nested.copy())
+	}
+	unlock()
+
+	for _, nested := range snapshots {
+		if rw.sanitizer != nil {
+			rw.sanitizer.testSuite(nested)
+		}
+
+		if err := rw.encode(nested.render(rw.suites.precision()), "testsuite", 2); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Close ends the current suite, if any, closes the testsuites tag, and
+// back-patches the report counters. If the report was written to a temporary
+// file, it is copied to the underlying writer and removed. It doesn't close
+// the underlying writer.
+func (rw *ReportWriter) Close() error {
+	if rw.closed {
+		return nil
+	}
+	defer rw.removeSpool()
+
+	if rw.suite != nil {
+		if err := rw.EndSuite(); err != nil {
+			return err
+		}
+	}
+
+	rw.closed = true
+	if err := rw.writeString("\n</testsuites>"); err != nil {
+		return err
+	}
+
+	suites := rw.suites
+	suites.resolveTime()
+	if err := rw.patch(rw.rootAt, suites.Tests, suites.Failures, suites.Errors, suites.Skipped, suites.Time); err != nil {
+		return err
+	}
+
+	if err := rw.out.Flush(); err != nil {
+		return err
+	}
+
+	if rw.spool == nil {
+		return nil
+	}
+
+	if _, err := rw.spool.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	_, err := io.Copy(rw.target, rw.spool)
+	return err
+}
+
+// removeSpool closes and removes the temporary file the report is written to,
+// if any
+func (rw *ReportWriter) removeSpool() {
+	if rw.spool == nil {
+		return
+	}
+
+	_ = rw.spool.Close()
+	_ = os.Remove(rw.spool.Name())
+	rw.spool = nil
+}
+
+// writeStartTag writes a start tag with the given indentation and attributes.
+// Space is reserved for the counters and the offset of that space is returned.
+func (rw *ReportWriter) writeStartTag(name string, indent string, attrs ...xml.Attr) (int64, error) {
+	tag := strings.Builder{}
+	if len(indent) > 0 {
+		tag.WriteString("\n")
+	}
+	tag.WriteString(indent + "<" + name)
+	for _, attr := range attrs {
+		writeAttr(&tag, attr.Name.Local, attr.Value)
+	}
+
+	if err := rw.writeString(tag.String()); err != nil {
+		return 0, err
+	}
+
+	offset := rw.offset
+	if err := rw.writeString(strings.Repeat(" ", rw.width)); err != nil {
+		return 0, err
+	}
+
+	return offset, rw.writeString(">")
+}
+
+// patch overwrites the space reserved at offset with the given counters
+func (rw *ReportWriter) patch(offset int64, tests int, failures int, errs int, skipped int, time Duration) error {
+	counters := formatCounters(tests, failures, errs, skipped, time, rw.suites.precision())
+	if len(counters) > rw.width {
+		return fmt.Errorf("cannot write counters: %d bytes don't fit in the %d reserved", len(counters), rw.width)
+	}
+
+	if err := rw.out.Flush(); err != nil {
+		return err
+	}
+
+	if _, err := rw.seeker.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(rw.seeker, counters+strings.Repeat(" ", rw.width-len(counters))); err != nil {
+		return err
+	}
+
+	written, err := rw.seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if written != offset+int64(rw.width) {
+		return errors.New("cannot write counters: the writer didn't write them at the reserved space")
+	}
+
+	_, err = rw.seeker.Seek(rw.offset, io.SeekStart)
+	return err
+}
+
+// patchOffset returns the current offset of w if the counters written to it
+// can be back-patched. It can't be done if w can't seek, eg: a pipe, or if it
+// writes everything at the end, eg: a file opened with os.O_APPEND. To find
+// out, the last byte of the XML header, which must already be written to w, is
+// written again.
+func patchOffset(w io.Writer) (int64, bool) {
+	seeker, ok := w.(io.WriteSeeker)
+	if !ok {
+		return 0, false
+	}
+
+	end, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil || end == 0 {
+		return 0, false
+	}
+
+	if _, err := seeker.Seek(end-1, io.SeekStart); err != nil {
+		return 0, false
+	}
+
+	if _, err := io.WriteString(seeker, xml.Header[len(xml.Header)-1:]); err != nil {
+		return 0, false
+	}
+
+	offset, err := seeker.Seek(0, io.SeekCurrent)
+	return offset, err == nil && offset == end
+}
+
+// encode writes v as an element with the given name and indentation depth
+func (rw *ReportWriter) encode(v interface{}, name string, depth int) error {
+	rw.buffer.Reset()
+	rw.buffer.WriteString("\n")
+
+	encoder := xml.NewEncoder(&rw.buffer)
+	encoder.Indent(strings.Repeat("    ", depth), "    ")
+
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	if err := encoder.EncodeElement(v, start); err != nil {
+		return err
+	}
+
+	if err := encoder.Flush(); err != nil {
+		return err
+	}
+
+	return rw.write(rw.buffer.Bytes())
+}
+
+// attr returns an attribute with the given name and the value sanitized
+func (rw *ReportWriter) attr(name string, value string) xml.Attr {
+	return xml.Attr{Name: xml.Name{Local: name}, Value: rw.sanitize(value)}
+}
+
+// sanitize returns s sanitized by the writer sanitizer, if any
+func (rw *ReportWriter) sanitize(s string) string {
+	if rw.sanitizer == nil {
+		return s
+	}
+
+	return rw.sanitizer.Sanitize(s)
+}
+
+// sanitizeProperties returns properties sanitized by the writer sanitizer, if
+// any
+func (rw *ReportWriter) sanitizeProperties(properties *Properties) *Properties {
+	if rw.sanitizer == nil {
+		return properties
+	}
+
+	sanitized := properties.copy()
+	rw.sanitizer.properties(sanitized)
+	return sanitized
+}
+
+func (rw *ReportWriter) writeString(s string) error {
+	return rw.write([]byte(s))
+}
+
+func (rw *ReportWriter) write(p []byte) error {
+	n, err := rw.out.Write(p)
+	rw.offset += int64(n)
+	return err
+}
+
+// writeAttr writes an attribute to tag, omitting it if value is empty
+func writeAttr(tag *strings.Builder, name string, value string) {
+	if len(value) == 0 {
+		return
+	}
+
+	tag.WriteString(" " + name + `="`)
+	_ = xml.EscapeText(tag, []byte(value))
+	tag.WriteString(`"`)
+}
+
+// formatCounters returns the counter attributes of a testsuites or testsuite
+// tag, with the time formatted with the given precision
+func formatCounters(tests int, failures int, errs int, skipped int, time Duration, precision int) string {
+	counters := fmt.Sprintf(
+		` tests="%d" failures="%d" errors="%d" skipped="%d"`,
+		tests,
+		failures,
+		errs,
+		skipped,
+	)
+
+	if time != 0 {
+		counters += fmt.Sprintf(` time="%s"`, time.Format(precision))
+	}
+
+	return counters
+}
+
+// maxCountersWidth returns the length of the longest counter attributes with
+// the given precision, which is the space reserved for them
+func maxCountersWidth(precision int) int {
+	return len(formatCounters(math.MinInt, math.MinInt, math.MinInt, math.MinInt, Duration(math.MinInt64), precision))
+}
@@ -0,0 +1,234 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeStreamReport(t *testing.T, rw *ReportWriter) {
+	suite1 := NewTestSuite("testsuite#1", "suite 1")
+	suite1.SetProperty("database", "postgres 15")
+	err := suite1.AddTestCase(NewTestCase("case#1", "case 1", "report.TestReportWriter"))
+	assert.Nil(t, err)
+
+	err = rw.StartSuite(suite1)
+	assert.Nil(t, err)
+
+	case2 := NewTestCase("case#2", "case 2", "report.TestReportWriter")
+	case2.Time = 1500000000
+	case2.AddFailure(NewFailure("msg", "type", `"quoted" <content>`))
+	case2.AddError(NewError("msg", "type", "error"))
+	err = rw.WriteTestCase(case2)
+	assert.Nil(t, err)
+
+	suite1.SystemOut = "suite output"
+	err = rw.EndSuite()
+	assert.Nil(t, err)
+
+	err = rw.StartSuite(NewAnonymousTestSuite())
+	assert.Nil(t, err)
+
+	for i := 0; i < 100; i++ {
+		testCase := NewTestCase(fmt.Sprintf("case#%d", i), "case", "")
+		if i%10 == 0 {
+			testCase.Skip("skip")
+		}
+
+		err = rw.WriteTestCase(testCase)
+		assert.Nil(t, err)
+	}
+
+	err = rw.Close()
+	assert.Nil(t, err)
+}
+
+func TestReportWriter_Seeker(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "report.xml")
+	f, err := os.Create(filename)
+	assert.Nil(t, err)
+	defer f.Close()
+
+	header := NewTestSuites("testsuites#1", "stream & report")
+	header.SetProperty("version", "1.0.0")
+
+	rw, err := NewReportWriter(f, header)
+	assert.Nil(t, err)
+	writeStreamReport(t, rw)
+
+	assert.Equal(t, 102, header.Tests)
+	assert.Equal(t, 1, header.Failures)
+	assert.Equal(t, 1, header.Errors)
+	assert.Equal(t, 10, header.Skipped)
+	assert.Equal(t, Duration(1500000000), header.Time)
+	assert.Empty(t, header.TestSuites)
+
+	content, err := os.ReadFile(filename)
+	assert.Nil(t, err)
+	assert.Contains(t, string(content), `<testsuites id="testsuites#1" name="stream &amp; report" tests="102" failures="1" errors="1" skipped="10" time="1.500"`)
+	assert.Contains(t, string(content), `<testsuite id="testsuite#1" name="suite 1" tests="2" failures="1" errors="1" skipped="0" time="1.500"`)
+	assert.Contains(t, string(content), `<testsuite tests="100" failures="0" errors="0" skipped="10"`)
+
+	parsed, err := ParseReport(bytes.NewReader(content))
+	assert.Nil(t, err)
+	assert.Equal(t, 102, parsed.Tests)
+	assert.Equal(t, "stream & report", parsed.Name)
+	assert.Equal(t, `"quoted" <content>`, parsed.TestSuites[0].TestCases[1].Failures[0].Content)
+	assert.Equal(t, "suite output", parsed.TestSuites[0].SystemOut)
+
+	value, ok := parsed.GetProperty("version")
+	assert.True(t, ok)
+	assert.Equal(t, "1.0.0", value)
+}
+
+func TestReportWriter_Append(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "report.xml")
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	assert.Nil(t, err)
+	defer f.Close()
+
+	header := NewTestSuites("id", "name")
+	rw, err := NewReportWriter(f, header)
+	assert.Nil(t, err)
+	writeStreamReport(t, rw)
+
+	content, err := os.ReadFile(filename)
+	assert.Nil(t, err)
+	assert.Contains(t, string(content), `<testsuites id="id" name="name" tests="102" failures="1" errors="1" skipped="10" time="1.500"`)
+	assert.True(t, bytes.HasSuffix(content, []byte("</testsuites>")))
+
+	parsed, err := ParseReport(bytes.NewReader(content))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, parsed.Failures)
+	assert.Equal(t, 1, parsed.TestSuites[0].Failures)
+}
+
+func TestReportWriter_Writer(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	header := NewTestSuites("id", "name")
+
+	rw, err := NewReportWriter(buffer, header)
+	assert.Nil(t, err)
+	writeStreamReport(t, rw)
+
+	assert.Equal(t, 102, header.Tests)
+	assert.Contains(t, buffer.String(), `<testsuites id="id" name="name" tests="102" failures="`)
+
+	parsed, err := ParseReport(buffer)
+	assert.Nil(t, err)
+	assert.Equal(t, 102, parsed.Tests)
+	assert.Equal(t, 10, parsed.Skipped)
+}
+
+func TestReportWriter_Attributes(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	header := NewTestSuites("id", "name")
+	header.Hostname = "ci-runner"
+
+	rw, err := NewReportWriter(buffer, header)
+	assert.Nil(t, err)
+
+	testCase := NewTestCase("case#1", "case 1", "")
+	testCase.startTime = time.Date(2021, 3, 4, 15, 4, 5, 0, time.UTC)
+	suite := NewTestSuite("suite#1", "suite 1")
+	suite.Package = "report"
+	assert.Nil(t, suite.AddTestCase(testCase))
+
+	assert.Nil(t, rw.StartSuite(suite))
+	assert.Equal(t, []*TestCase{testCase}, suite.TestCases)
+	assert.Nil(t, rw.Close())
+
+	assert.Equal(t, 1, suite.Tests)
+	assert.Contains(t, buffer.String(), `<testsuites id="id" name="name" hostname="ci-runner" tests="1" `)
+	assert.Contains(t, buffer.String(), `<testsuite id="suite#1" name="suite 1" package="report" timestamp="2021-03-04T15:04:05Z" tests="1" `)
+}
+
+func TestReportWriter_WallTime(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "report.xml")
+	f, err := os.Create(filename)
+	assert.Nil(t, err)
+	defer f.Close()
+
+	header := NewAnonymousTestSuites()
+	rw, err := NewReportWriter(f, header)
+	assert.Nil(t, err)
+
+	suite := NewAnonymousTestSuite()
+	assert.Nil(t, rw.StartSuite(suite))
+	assert.Nil(t, rw.WriteTestCase(&TestCase{Time: 1500000000}))
+	suite.WallTime = 2000000000
+	assert.Nil(t, rw.EndSuite())
+
+	header.WallTime = 2500000000
+	assert.Nil(t, rw.Close())
+
+	content, err := os.ReadFile(filename)
+	assert.Nil(t, err)
+	assert.Contains(t, string(content), `<testsuites tests="1" failures="0" errors="0" skipped="0" time="2.500"`)
+	assert.Contains(t, string(content), `<testsuite tests="1" failures="0" errors="0" skipped="0" time="2.000"`)
+}
+
+func TestReportWriter_Nested(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	header := NewAnonymousTestSuites()
+	rw, err := NewReportWriter(buffer, header)
+	assert.Nil(t, err)
+
+	nested := NewTestSuite("nested", "nested")
+	nested.TestCases = []*TestCase{NewTestCase("", "case 2", "")}
+	suite := NewTestSuite("suite", "suite")
+	suite.TestSuites = []*TestSuite{nested}
+	assert.Nil(t, rw.StartSuite(suite))
+	assert.Nil(t, rw.WriteTestCase(NewTestCase("", "case 1", "")))
+	assert.Nil(t, rw.Close())
+
+	assert.Equal(t, 2, suite.Tests)
+	assert.Equal(t, 2, header.Tests)
+
+	parsed, err := ParseReport(buffer)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, parsed.Tests)
+	assert.Equal(t, "case 1", parsed.TestSuites[0].TestCases[0].Name)
+	assert.Equal(t, "case 2", parsed.TestSuites[0].TestSuites[0].TestCases[0].Name)
+}
+
+func TestReportWriter_Errors(t *testing.T) {
+	rw, err := NewReportWriter(&bytes.Buffer{}, NewAnonymousTestSuites())
+	assert.Nil(t, err)
+
+	assert.NotNil(t, rw.WriteTestCase(NewAnonymousTestCase()))
+	assert.NotNil(t, rw.EndSuite())
+
+	assert.Nil(t, rw.Close())
+	assert.Nil(t, rw.Close())
+	assert.NotNil(t, rw.StartSuite(NewAnonymousTestSuite()))
+}
+
+func TestReportWriter_Precision(t *testing.T) {
+	header := NewAnonymousTestSuites()
+	header.Precision = 200
+
+	buffer := &bytes.Buffer{}
+	rw, err := NewReportWriter(buffer, header)
+	assert.Nil(t, err)
+
+	assert.Nil(t, rw.StartSuite(NewAnonymousTestSuite()))
+	assert.Nil(t, rw.WriteTestCase(&TestCase{Time: 1500000000}))
+	assert.Nil(t, rw.Close())
+
+	parsed, err := ParseReport(buffer)
+	assert.Nil(t, err)
+	assert.Equal(t, Duration(1500000000), parsed.Time)
+}
+
+func TestFormatCounters(t *testing.T) {
+	actual := formatCounters(1, 2, 3, 4, 1500000000, 3)
+
+	assert.Equal(t, ` tests="1" failures="2" errors="3" skipped="4" time="1.500"`, actual)
+	assert.Greater(t, maxCountersWidth(3), len(actual))
+}
@@ -0,0 +1,92 @@
+package report
+
+// summarySeparator joins the names of nested suites in summaries
+const summarySeparator = " / "
+
+// summaryTitle is the title of summaries of reports without a name
+const summaryTitle = "Test report"
+
+// summary flattens and, if there is a Sanitizer, sanitizes a snapshot of the
+// report in place and returns it. It is what the Markdown and HTML renderers
+// show, and its Precision is the one the times are shown with.
+func (suites *TestSuites) summary() *TestSuites {
+	suites.Precision = suites.precision()
+	suites.flatten(summarySeparator)
+	if suites.Sanitizer != nil {
+		suites.Sanitizer.testSuites(suites)
+	}
+
+	return suites
+}
+
+// title returns the report name or ID, or summaryTitle if it has neither
+func (suites *TestSuites) title() string {
+	if len(suites.Name) == 0 && len(suites.ID) == 0 {
+		return summaryTitle
+	}
+
+	return displayName(suites.Name, suites.ID)
+}
+
+// passed returns the amount of test cases in the suite that weren't skipped and
+// have no failures or errors
+func (suite *TestSuite) passed() int {
+	total := 0
+	for _, testCase := range suite.TestCases {
+		if testCase.Skipped == nil && testCase.passed() {
+			total++
+		}
+	}
+
+	for _, nested := range suite.TestSuites {
+		total += nested.passed()
+	}
+
+	return total
+}
+
+// passed returns the amount of test cases in the report that weren't skipped
+// and have no failures or errors
+func (suites *TestSuites) passed() int {
+	total := 0
+	for _, suite := range suites.TestSuites {
+		total += suite.passed()
+	}
+
+	return total
+}
+
+// status returns the outcome of the test case: failed, error, skipped, or
+// passed. A test case with both failures and errors is failed.
+func (testCase *TestCase) status() string {
+	switch {
+	case len(testCase.Failures) > 0:
+		return "failed"
+	case len(testCase.Errors) > 0:
+		return "error"
+	case testCase.Skipped != nil:
+		return "skipped"
+	default:
+		return "passed"
+	}
+}
+
+// displayName returns name, or id if name is empty, or a placeholder if both
+// are empty
+func displayName(name string, id string) string {
+	if len(name) > 0 {
+		return name
+	}
+
+	if len(id) > 0 {
+		return id
+	}
+
+	return "(unnamed)"
+}
+
+// durationLabel returns d in seconds with the given number of decimal places
+// and a unit, eg: 1.500s
+func durationLabel(d Duration, precision int) string {
+	return d.Format(precision) + "s"
+}
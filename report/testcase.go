@@ -1,6 +1,10 @@
 package report
 
-import "time"
+import (
+	"io"
+	"sync"
+	"time"
+)
 
 // TestCase maps to a testcase tag which represents a test case. It has
 // the following fields:
@@ -8,22 +12,52 @@ import "time"
 // if empty. If given, an ID must be unique in the test suite.
 // Name: optional test case name. Maps to the name attribute. The attribute is
 // omitted if empty.
-// Time: optional duration of the test. Maps to the time attribute. Omitted if
-// empty.
+// Time: optional duration of the test. Maps to the time attribute, written in
+// seconds. Omitted if empty.
 // Classname: optional name of the module beiong tested. Maps to the classname
 // attribute. Omitted if empty.
+// File: optional source file of the test. Maps to the file attribute. Omitted
+// if empty.
+// Line: optional line of the test in File. Maps to the line attribute. Omitted
+// if empty.
 // Content: optional text content of the test. Maps to the content of the tag.
+// Properties: optional test case properties. Maps to the properties tag.
+// Omitted if nil.
+// Skipped: optional skip reason. Maps to the skipped tag. Omitted if nil.
 // Failures: test failures. Each element maps to its own failure tag.
 // Errors: test errors. Each element maps to its own error tag.
+// FlakyFailures: failed attempts of a test that eventually passed. Each element
+// maps to its own flakyFailure tag.
+// FlakyErrors: errored attempts of a test that eventually passed. Each element
+// maps to its own flakyError tag.
+// RerunFailures: failed reruns of a test that never passed. Each element maps
+// to its own rerunFailure tag.
+// RerunErrors: errored reruns of a test that never passed. Each element maps to
+// its own rerunError tag.
+// SystemOut: optional standard output of the test. Maps to the system-out tag.
+// Omitted if empty.
+// SystemErr: optional standard error of the test. Maps to the system-err tag.
+// Omitted if empty.
 type TestCase struct {
-	ID        string        `xml:"id,attr,omitempty"`
-	Name      string        `xml:"name,attr,omitempty"`
-	Time      time.Duration `xml:"time,attr,omitempty"`
-	Classname string        `xml:"classname,attr,omitempty"`
-	Content   string        `xml:",chardata"`
-	Failures  []*Failure    `xml:"failure"`
-	Errors    []*Error      `xml:"error"`
-	startTime time.Time     `xml:"-"`
+	ID            string      `xml:"id,attr,omitempty" json:"id,omitempty"`
+	Name          string      `xml:"name,attr,omitempty" json:"name,omitempty"`
+	Time          Duration    `xml:"time,attr,omitempty" json:"time,omitempty"`
+	Classname     string      `xml:"classname,attr,omitempty" json:"classname,omitempty"`
+	File          string      `xml:"file,attr,omitempty" json:"file,omitempty"`
+	Line          int         `xml:"line,attr,omitempty" json:"line,omitempty"`
+	Content       string      `xml:",chardata" json:"content,omitempty"`
+	Properties    *Properties `xml:"properties,omitempty" json:"properties,omitempty"`
+	Skipped       *Skipped    `xml:"skipped,omitempty" json:"skipped,omitempty"`
+	Failures      []*Failure  `xml:"failure" json:"failures,omitempty"`
+	Errors        []*Error    `xml:"error" json:"errors,omitempty"`
+	FlakyFailures []*Rerun    `xml:"flakyFailure" json:"flakyFailures,omitempty"`
+	FlakyErrors   []*Rerun    `xml:"flakyError" json:"flakyErrors,omitempty"`
+	RerunFailures []*Rerun    `xml:"rerunFailure" json:"rerunFailures,omitempty"`
+	RerunErrors   []*Rerun    `xml:"rerunError" json:"rerunErrors,omitempty"`
+	SystemOut     string      `xml:"system-out,omitempty" json:"system-out,omitempty"`
+	SystemErr     string      `xml:"system-err,omitempty" json:"system-err,omitempty"`
+	startTime     time.Time   `xml:"-" json:"-"`
+	mu            sync.RWMutex
 }
 
 // NewTestCase returns a test case with the given id, name, and classname
@@ -40,27 +74,132 @@ func NewAnonymousTestCase() *TestCase {
 	return &TestCase{}
 }
 
+// SetLocation sets the source file and line of the test case
+func (testCase *TestCase) SetLocation(file string, line int) {
+	testCase.mu.Lock()
+	defer testCase.mu.Unlock()
+
+	testCase.File = file
+	testCase.Line = line
+}
+
 // SetContent sets the test case content
 func (testCase *TestCase) SetContent(c string) {
+	testCase.mu.Lock()
+	defer testCase.mu.Unlock()
+
 	testCase.Content = c
 }
 
+// SystemOutWriter returns a writer that appends to the test case system-out
+func (testCase *TestCase) SystemOutWriter() io.Writer {
+	return outputWriter{output: &testCase.SystemOut, mu: &testCase.mu}
+}
+
+// SystemErrWriter returns a writer that appends to the test case system-err
+func (testCase *TestCase) SystemErrWriter() io.Writer {
+	return outputWriter{output: &testCase.SystemErr, mu: &testCase.mu}
+}
+
+// SetProperty sets the value of the test case property with the given name
+func (testCase *TestCase) SetProperty(name string, value string) {
+	testCase.mu.Lock()
+	defer testCase.mu.Unlock()
+
+	if testCase.Properties == nil {
+		testCase.Properties = NewProperties()
+	}
+
+	testCase.Properties.Set(name, value)
+}
+
+// GetProperty returns the value of the test case property with the given name
+// and whether it exists
+func (testCase *TestCase) GetProperty(name string) (string, bool) {
+	testCase.mu.RLock()
+	defer testCase.mu.RUnlock()
+
+	return testCase.Properties.Get(name)
+}
+
+// Skip marks the test case as skipped with the given message
+func (testCase *TestCase) Skip(msg string) {
+	testCase.mu.Lock()
+	defer testCase.mu.Unlock()
+
+	testCase.Skipped = &Skipped{
+		Message: msg,
+	}
+}
+
+// SetSkipped marks the test case as skipped with the given Skipped
+func (testCase *TestCase) SetSkipped(s *Skipped) {
+	testCase.mu.Lock()
+	defer testCase.mu.Unlock()
+
+	testCase.Skipped = s
+}
+
 // AddFailure adds a failure to the test case
 func (testCase *TestCase) AddFailure(f *Failure) {
+	testCase.mu.Lock()
+	defer testCase.mu.Unlock()
+
 	testCase.Failures = append(testCase.Failures, f)
 }
 
 // AddError adds an error to the test case
 func (testCase *TestCase) AddError(e *Error) {
+	testCase.mu.Lock()
+	defer testCase.mu.Unlock()
+
 	testCase.Errors = append(testCase.Errors, e)
 }
 
+// AddFlakyFailure adds a flaky failure to the test case
+func (testCase *TestCase) AddFlakyFailure(r *Rerun) {
+	testCase.mu.Lock()
+	defer testCase.mu.Unlock()
+
+	testCase.FlakyFailures = append(testCase.FlakyFailures, r)
+}
+
+// AddFlakyError adds a flaky error to the test case
+func (testCase *TestCase) AddFlakyError(r *Rerun) {
+	testCase.mu.Lock()
+	defer testCase.mu.Unlock()
+
+	testCase.FlakyErrors = append(testCase.FlakyErrors, r)
+}
+
+// AddRerunFailure adds a rerun failure to the test case
+func (testCase *TestCase) AddRerunFailure(r *Rerun) {
+	testCase.mu.Lock()
+	defer testCase.mu.Unlock()
+
+	testCase.RerunFailures = append(testCase.RerunFailures, r)
+}
+
+// AddRerunError adds a rerun error to the test case
+func (testCase *TestCase) AddRerunError(r *Rerun) {
+	testCase.mu.Lock()
+	defer testCase.mu.Unlock()
+
+	testCase.RerunErrors = append(testCase.RerunErrors, r)
+}
+
 // Start sets the time the test started
 func (testCase *TestCase) Start() {
+	testCase.mu.Lock()
+	defer testCase.mu.Unlock()
+
 	testCase.startTime = time.Now()
 }
 
 // End sets the test cases duration
 func (testCase *TestCase) End() {
-	testCase.Time = time.Since(testCase.startTime)
+	testCase.mu.Lock()
+	defer testCase.mu.Unlock()
+
+	testCase.Time = Duration(time.Since(testCase.startTime))
 }
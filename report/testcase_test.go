@@ -1,6 +1,9 @@
 package report
 
 import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -37,6 +40,59 @@ func TestSetContent(t *testing.T) {
 	assert.Equal(t, expected, actual)
 }
 
+func TestTestCaseSystemOutWriter(t *testing.T) {
+	actual := NewAnonymousTestCase()
+	fmt.Fprintln(actual.SystemOutWriter(), "line 1")
+	fmt.Fprintln(actual.SystemOutWriter(), "line 2")
+	fmt.Fprint(actual.SystemErrWriter(), "err")
+
+	expected := &TestCase{
+		SystemOut: "line 1\nline 2\n",
+		SystemErr: "err",
+	}
+
+	assert.Equal(t, expected, actual)
+}
+
+func TestTestCaseProperty(t *testing.T) {
+	actual := NewAnonymousTestCase()
+
+	_, ok := actual.GetProperty("name")
+	assert.False(t, ok)
+
+	actual.SetProperty("name", "value")
+	value, ok := actual.GetProperty("name")
+	assert.True(t, ok)
+	assert.Equal(t, "value", value)
+}
+
+func TestSkip(t *testing.T) {
+	actual := NewAnonymousTestCase()
+	actual.Skip("message")
+
+	expected := &TestCase{
+		Skipped: &Skipped{
+			Message: "message",
+		},
+	}
+
+	assert.Equal(t, expected, actual)
+}
+
+func TestSetSkipped(t *testing.T) {
+	actual := NewAnonymousTestCase()
+	actual.SetSkipped(NewSkipped("message", "content"))
+
+	expected := &TestCase{
+		Skipped: &Skipped{
+			Message: "message",
+			Content: "content",
+		},
+	}
+
+	assert.Equal(t, expected, actual)
+}
+
 func TestAddFailure_One(t *testing.T) {
 	actual := NewAnonymousTestCase()
 	actual.AddFailure(NewAnonymousFailure("content"))
@@ -131,3 +187,24 @@ func TestEnd(t *testing.T) {
 
 	assert.NotNil(t, actual.Time)
 }
+
+func TestTestCaseSetLocation(t *testing.T) {
+	testCase := NewTestCase("", "name", "class")
+	testCase.SetLocation("api_test.go", 42)
+
+	actual, err := xml.Marshal(testCase)
+	assert.Nil(t, err)
+	assert.Equal(t, `<TestCase name="name" classname="class" file="api_test.go" line="42"></TestCase>`, string(actual))
+
+	suites := NewAnonymousTestSuites()
+	suite := NewAnonymousTestSuite()
+	suite.TestCases = []*TestCase{testCase}
+	suites.TestSuites = []*TestSuite{suite}
+	content, err := suites.MakeReport()
+	assert.Nil(t, err)
+
+	parsed, err := ParseReport(bytes.NewReader(content))
+	assert.Nil(t, err)
+	assert.Equal(t, "api_test.go", parsed.TestSuites[0].TestCases[0].File)
+	assert.Equal(t, 42, parsed.TestSuites[0].TestCases[0].Line)
+}
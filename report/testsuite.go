@@ -2,58 +2,156 @@ package report
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"sync"
 	"time"
 )
 
-// TestSuite maps to a testsuite tag which represents a set of test cases. It
-// has the following fields:
+// TestSuite maps to a testsuite tag which represents a set of test cases and,
+// in hierarchical reports, nested test suites. It has the following fields:
 // ID:optional test suite ID. Maps to the id attribute. The attribute is omitted
 // if empty. If given, an ID must be unique in the test suites.
 // Name: optional test suite name. Maps to the name attribute. The attribute is
 // omitted if empty.
-// Time: optional duration of the suite. Maps to the time attribute. Omitted if
-// empty. This field is calculated automatically by Testsuites.MakeReport().
-// Tests: total amount of test cases in the suite. Maps to the tests attribute.
-// This field is calculated automatically by Testsuites.MakeReport().
-// Failures: total amount of failures cases in the suite. Maps to the failures
+// Package: optional package of the tested code. Maps to the package attribute.
+// Omitted if empty.
+// Timestamp: optional time the suite started, written in ISO-8601. Maps to the
+// timestamp attribute. Omitted if empty. If not set, Testsuites.MakeReport()
+// sets it to the time the first test case in the suite was started.
+// Hostname: optional name of the host that ran the suite. Maps to the hostname
+// attribute. Omitted if empty. It can be set automatically with WithHostname.
+// Time: optional duration of the suite. Maps to the time attribute, written in
+// seconds. Omitted if empty. This field is calculated automatically by
+// Testsuites.MakeReport(): it is WallTime if set, otherwise the sum of the test
+// case and nested suite times.
+// Tests: total amount of test cases in the suite, including the ones of nested
+// suites. Maps to the tests attribute. This field is calculated automatically
+// by Testsuites.MakeReport().
+// Failures: total amount of failed test cases in the suite. Maps to the
+// failures attribute. This field is calculated automatically by
+// Testsuites.MakeReport() according to TestSuites.CountMode.
+// Errors: total amount of test cases with errors in the suite. Maps to the
+// errors attribute. This field is calculated automatically by
+// Testsuites.MakeReport() according to TestSuites.CountMode.
+// Skipped: total amount of skipped test cases in the suite. Maps to the skipped
 // attribute. This field is calculated automatically by Testsuites.MakeReport().
-// Errors: total amount of errors in the suite. Maps to the errors attribute.
-// This field is calculated automatically by Testsuites.MakeReport().
+// Properties: optional suite properties. Maps to the properties tag. Omitted
+// if nil.
 // TestCases: test cases in the suite. Each element maps to its own testcase
 // tag.
+// TestSuites: optional nested test suites. Each element maps to its own
+// testsuite tag inside the suite. Not every consumer supports nesting, see
+// TestSuites.Flatten.
+// SystemOut: optional standard output of the suite, eg: setup logs. Maps to the
+// system-out tag. Omitted if empty.
+// SystemErr: optional standard error of the suite. Maps to the system-err tag.
+// Omitted if empty.
+// WallTime: optional wall-clock duration of the suite, including the time
+// spent outside test cases, eg: setup. Set by End(). Not written to the report.
+// HookCaseNamer: optional function that names the test cases added by Setup
+// and Teardown. DefaultHookNamer is used if nil. Not written to the report.
+// CaptureLocation: if set, AddTestCase, Run, RunWithRetry, Setup, and Teardown
+// record the file and line they were called from in the test cases they add
+// without a file, so CI tools can link failures to the source. Not written to
+// the report.
+// LocationRoot: optional directory the captured file names are made relative
+// to, see CallerLocation. Not written to the report.
 type TestSuite struct {
-	ID        string        `xml:"id,attr,omitempty"`
-	Name      string        `xml:"name,attr,omitempty"`
-	Time      time.Duration `xml:"time,attr,omitempty"`
-	Tests     int           `xml:"tests,attr"`
-	Failures  int           `xml:"failures,attr"`
-	Errors    int           `xml:"errors,attr"`
-	TestCases []*TestCase   `xml:"testcase,omitempty"`
+	ID              string       `xml:"id,attr,omitempty" json:"id,omitempty"`
+	Name            string       `xml:"name,attr,omitempty" json:"name,omitempty"`
+	Package         string       `xml:"package,attr,omitempty" json:"package,omitempty"`
+	Timestamp       Timestamp    `xml:"timestamp,attr" json:"timestamp"`
+	Hostname        string       `xml:"hostname,attr,omitempty" json:"hostname,omitempty"`
+	Time            Duration     `xml:"time,attr,omitempty" json:"time,omitempty"`
+	Tests           int          `xml:"tests,attr" json:"tests"`
+	Failures        int          `xml:"failures,attr" json:"failures"`
+	Errors          int          `xml:"errors,attr" json:"errors"`
+	Skipped         int          `xml:"skipped,attr" json:"skipped"`
+	Properties      *Properties  `xml:"properties,omitempty" json:"properties,omitempty"`
+	TestCases       []*TestCase  `xml:"testcase,omitempty" json:"testcases,omitempty"`
+	TestSuites      []*TestSuite `xml:"testsuite,omitempty" json:"testsuites,omitempty"`
+	SystemOut       string       `xml:"system-out,omitempty" json:"system-out,omitempty"`
+	SystemErr       string       `xml:"system-err,omitempty" json:"system-err,omitempty"`
+	WallTime        Duration     `xml:"-" json:"-"`
+	HookCaseNamer   HookNamer    `xml:"-" json:"-"`
+	CaptureLocation bool         `xml:"-" json:"-"`
+	LocationRoot    string       `xml:"-" json:"-"`
+	startTime       time.Time    `xml:"-" json:"-"`
+	mu              sync.RWMutex
+}
+
+// SuiteOption changes how a TestSuite or TestSuites is created
+type SuiteOption func(*suiteOptions)
+
+type suiteOptions struct {
+	hostname bool
+}
+
+// WithHostname sets the hostname attribute to the name of the current host, as
+// reported by os.Hostname. The attribute is left empty if the host name can't
+// be found.
+func WithHostname() SuiteOption {
+	return func(o *suiteOptions) {
+		o.hostname = true
+	}
+}
+
+// hostnameValue returns the name of the current host if the options ask for it
+func (o *suiteOptions) hostnameValue() string {
+	if !o.hostname {
+		return ""
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return ""
+	}
+
+	return hostname
+}
+
+func newSuiteOptions(opts []SuiteOption) *suiteOptions {
+	options := &suiteOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	return options
 }
 
 // NewTestSuite returns a new TestSuite with the given id and name
-func NewTestSuite(id string, name string) *TestSuite {
+func NewTestSuite(id string, name string, opts ...SuiteOption) *TestSuite {
 	return &TestSuite{
 		ID:       id,
 		Name:     name,
+		Hostname: newSuiteOptions(opts).hostnameValue(),
 		Tests:    0,
 		Failures: 0,
 		Errors:   0,
+		Skipped:  0,
 	}
 }
 
 // NewAnonymousTestSuite returns a new empty TestSuite
-func NewAnonymousTestSuite() *TestSuite {
+func NewAnonymousTestSuite(opts ...SuiteOption) *TestSuite {
 	return &TestSuite{
+		Hostname: newSuiteOptions(opts).hostnameValue(),
 		Tests:    0,
 		Failures: 0,
 		Errors:   0,
+		Skipped:  0,
 	}
 }
 
 // AddTestCase adds a TestCase to the suite. If the test case has an ID, it must
-// be unique within the suite. If it isn't an error is returned.
+// be unique within the suite. If it isn't an error is returned. If
+// CaptureLocation is set, the file and line AddTestCase was called from are
+// recorded in the test case, unless it already has a file.
 func (suite *TestSuite) AddTestCase(testcase *TestCase) error {
+	suite.mu.Lock()
+	defer suite.mu.Unlock()
+
 	if len(testcase.ID) > 0 {
 		for _, c := range suite.TestCases {
 			if c.ID == testcase.ID {
@@ -66,13 +164,83 @@ func (suite *TestSuite) AddTestCase(testcase *TestCase) error {
 		}
 	}
 
+	testcase.mu.Lock()
+	suite.captureLocation(testcase, 1)
+	testcase.mu.Unlock()
+
 	suite.TestCases = append(suite.TestCases, testcase)
 	return nil
 }
 
+// addAnonymous adds a test case without an ID to the suite. Only test cases
+// with an ID can collide with the ones already in the suite, so it can't fail.
+func (suite *TestSuite) addAnonymous(testCase *TestCase) {
+	suite.mu.Lock()
+	defer suite.mu.Unlock()
+
+	suite.TestCases = append(suite.TestCases, testCase)
+}
+
+// AddTestSuite adds a nested TestSuite to the suite. If the nested suite has an
+// ID, it must be unique among the suites nested in the same suite. If it isn't
+// an error is returned.
+func (suite *TestSuite) AddTestSuite(nested *TestSuite) error {
+	suite.mu.Lock()
+	defer suite.mu.Unlock()
+
+	if len(nested.ID) > 0 {
+		for _, s := range suite.TestSuites {
+			if s.ID == nested.ID {
+				return fmt.Errorf(
+					"cannot add test suite: suite ID=%s already contains a nested suite with ID=%s",
+					suite.ID,
+					nested.ID,
+				)
+			}
+		}
+	}
+
+	suite.TestSuites = append(suite.TestSuites, nested)
+	return nil
+}
+
+// SystemOutWriter returns a writer that appends to the suite system-out
+func (suite *TestSuite) SystemOutWriter() io.Writer {
+	return outputWriter{output: &suite.SystemOut, mu: &suite.mu}
+}
+
+// SystemErrWriter returns a writer that appends to the suite system-err
+func (suite *TestSuite) SystemErrWriter() io.Writer {
+	return outputWriter{output: &suite.SystemErr, mu: &suite.mu}
+}
+
+// SetProperty sets the value of the suite property with the given name
+func (suite *TestSuite) SetProperty(name string, value string) {
+	suite.mu.Lock()
+	defer suite.mu.Unlock()
+
+	if suite.Properties == nil {
+		suite.Properties = NewProperties()
+	}
+
+	suite.Properties.Set(name, value)
+}
+
+// GetProperty returns the value of the suite property with the given name and
+// whether it exists
+func (suite *TestSuite) GetProperty(name string) (string, bool) {
+	suite.mu.RLock()
+	defer suite.mu.RUnlock()
+
+	return suite.Properties.Get(name)
+}
+
 // RemoveTestCase removes a test case with the given id from the suite if it
 // exists
 func (suite *TestSuite) RemoveTestCase(id string) {
+	suite.mu.Lock()
+	defer suite.mu.Unlock()
+
 	for i, testcase := range suite.TestCases {
 		if testcase.ID == id {
 			suite.TestCases = append(
@@ -83,3 +251,132 @@ func (suite *TestSuite) RemoveTestCase(id string) {
 		}
 	}
 }
+
+// Start sets the time the suite started. If the suite has no timestamp, it is
+// set to the current time.
+func (suite *TestSuite) Start() {
+	suite.mu.Lock()
+	defer suite.mu.Unlock()
+
+	suite.startTime = time.Now()
+	if suite.Timestamp.IsZero() {
+		suite.Timestamp = Timestamp(suite.startTime)
+	}
+}
+
+// End sets the wall-clock duration of the suite, which takes precedence over
+// the sum of the test case times
+func (suite *TestSuite) End() {
+	suite.mu.Lock()
+	defer suite.mu.Unlock()
+
+	suite.WallTime = Duration(time.Since(suite.startTime))
+}
+
+// SummedTime returns the sum of the test case and nested suite times,
+// regardless of WallTime
+func (suite *TestSuite) SummedTime() Duration {
+	defer suite.rlockAll()()
+
+	return suite.summedTime()
+}
+
+func (suite *TestSuite) summedTime() Duration {
+	var sum Duration
+	for _, testCase := range suite.TestCases {
+		sum += testCase.Time
+	}
+
+	for _, nested := range suite.TestSuites {
+		sum += nested.totalTime()
+	}
+
+	return sum
+}
+
+// totalTime returns WallTime if set, otherwise the sum of the test case and
+// nested suite times
+func (suite *TestSuite) totalTime() Duration {
+	if suite.WallTime != 0 {
+		return suite.WallTime
+	}
+
+	return suite.summedTime()
+}
+
+// resolve calculates the counters and time of the suite and of its nested
+// suites, and sets their timestamps if they are empty
+func (suite *TestSuite) resolve(mode CountMode) {
+	suite.reset()
+	for _, testCase := range suite.TestCases {
+		suite.count(testCase, mode)
+	}
+
+	for _, nested := range suite.TestSuites {
+		nested.resolve(mode)
+		suite.add(nested)
+	}
+
+	suite.resolveTime()
+	if suite.Timestamp.IsZero() {
+		suite.Timestamp = suite.firstStart()
+	}
+}
+
+// reset sets all automatically calculated values to 0
+func (suite *TestSuite) reset() {
+	suite.Tests = 0
+	suite.Failures = 0
+	suite.Errors = 0
+	suite.Skipped = 0
+	suite.Time = 0
+}
+
+// resolveTime sets Time to WallTime if it was measured. Time must already be
+// the sum of the test case and nested suite times.
+func (suite *TestSuite) resolveTime() {
+	if suite.WallTime != 0 {
+		suite.Time = suite.WallTime
+	}
+}
+
+// firstStart returns the time the first test case in the suite was started, or
+// a zero Timestamp if none was. Nested suites must already be resolved, so that
+// their timestamps are taken into account.
+func (suite *TestSuite) firstStart() Timestamp {
+	first := Timestamp{}
+	for _, testCase := range suite.TestCases {
+		if start := Timestamp(testCase.startTime); start.before(first) {
+			first = start
+		}
+	}
+
+	for _, nested := range suite.TestSuites {
+		if nested.Timestamp.before(first) {
+			first = nested.Timestamp
+		}
+	}
+
+	return first
+}
+
+// count adds the values of the test case to the suite totals, counting
+// failures and errors according to mode
+func (suite *TestSuite) count(testCase *TestCase, mode CountMode) {
+	suite.Tests++
+	suite.Failures += mode.countFailures(testCase)
+	suite.Errors += mode.countErrors(testCase)
+	if testCase.Skipped != nil {
+		suite.Skipped++
+	}
+	suite.Time += testCase.Time
+}
+
+// add adds the values of an already calculated nested suite to the suite totals
+func (suite *TestSuite) add(nested *TestSuite) {
+	suite.Tests += nested.Tests
+	suite.Failures += nested.Failures
+	suite.Errors += nested.Errors
+	suite.Skipped += nested.Skipped
+	suite.Time += nested.Time
+}
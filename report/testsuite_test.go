@@ -1,7 +1,10 @@
 package report
 
 import (
+	"fmt"
+	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -32,6 +35,16 @@ func TestNewAnonymousTestSuite(t *testing.T) {
 	assert.Equal(t, expected, actual)
 }
 
+func TestNewTestSuite_WithHostname(t *testing.T) {
+	hostname, err := os.Hostname()
+	assert.Nil(t, err)
+
+	assert.Equal(t, hostname, NewTestSuite("id", "name", WithHostname()).Hostname)
+	assert.Equal(t, hostname, NewAnonymousTestSuite(WithHostname()).Hostname)
+	assert.Equal(t, hostname, NewTestSuites("id", "name", WithHostname()).Hostname)
+	assert.Equal(t, hostname, NewAnonymousTestSuites(WithHostname()).Hostname)
+}
+
 func TestAddTestCase_Success(t *testing.T) {
 	actual := NewAnonymousTestSuite()
 	err := actual.AddTestCase(NewAnonymousTestCase())
@@ -60,6 +73,36 @@ func TestAddTestCase_Error(t *testing.T) {
 	assert.Equal(t, 2, len(actual.TestCases))
 }
 
+func TestAddNestedTestSuite(t *testing.T) {
+	suite := NewTestSuite("id", "name")
+
+	assert.Nil(t, suite.AddTestSuite(NewTestSuite("nested", "nested")))
+	assert.Nil(t, suite.AddTestSuite(NewAnonymousTestSuite()))
+	assert.NotNil(t, suite.AddTestSuite(NewTestSuite("nested", "other")))
+	assert.Equal(t, 2, len(suite.TestSuites))
+}
+
+func TestTestSuiteSystemOutWriter(t *testing.T) {
+	actual := NewAnonymousTestSuite()
+	fmt.Fprintln(actual.SystemOutWriter(), "line 1")
+	fmt.Fprint(actual.SystemErrWriter(), "err")
+
+	assert.Equal(t, "line 1\n", actual.SystemOut)
+	assert.Equal(t, "err", actual.SystemErr)
+}
+
+func TestTestSuiteProperty(t *testing.T) {
+	actual := NewAnonymousTestSuite()
+
+	_, ok := actual.GetProperty("name")
+	assert.False(t, ok)
+
+	actual.SetProperty("name", "value")
+	value, ok := actual.GetProperty("name")
+	assert.True(t, ok)
+	assert.Equal(t, "value", value)
+}
+
 func TestRemoveTestCase(t *testing.T) {
 	actual := NewAnonymousTestSuite()
 	err := actual.AddTestCase(NewTestCase("1", "name", "class"))
@@ -72,3 +115,30 @@ func TestRemoveTestCase(t *testing.T) {
 
 	assert.Equal(t, 1, len(actual.TestCases))
 }
+
+func TestTestSuiteStartEnd(t *testing.T) {
+	suite := NewTestSuite("id", "name")
+	suite.Start()
+	time.Sleep(time.Millisecond)
+	suite.End()
+
+	assert.False(t, suite.Timestamp.IsZero())
+	assert.GreaterOrEqual(t, suite.WallTime, Duration(time.Millisecond))
+}
+
+func TestTestSuiteStart_KeepsTimestamp(t *testing.T) {
+	timestamp := Timestamp(time.Date(2021, 3, 4, 15, 4, 5, 0, time.UTC))
+	suite := NewTestSuite("id", "name")
+	suite.Timestamp = timestamp
+	suite.Start()
+
+	assert.Equal(t, timestamp, suite.Timestamp)
+}
+
+func TestTestSuiteSummedTime(t *testing.T) {
+	suite := NewTestSuite("id", "name")
+	suite.TestCases = []*TestCase{{Time: 1000}, {Time: 2000}}
+	suite.WallTime = 5000
+
+	assert.Equal(t, Duration(3000), suite.SummedTime())
+}
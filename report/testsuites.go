@@ -3,7 +3,7 @@ package report
 import (
 	"encoding/xml"
 	"fmt"
-	"os"
+	"sync"
 	"time"
 )
 
@@ -12,49 +12,88 @@ import (
 // ID: optional ID. Maps to the id attribute. The attribute is omitted if empty.
 // Name: optional name. Maps to the name attribute. The attribute is omitted if
 // empty.
+// Timestamp: optional time the report started, written in ISO-8601. Maps to
+// the timestamp attribute. Omitted if empty. If not set, Testsuites.MakeReport()
+// sets it to the earliest suite timestamp.
+// Hostname: optional name of the host that ran the tests. Maps to the hostname
+// attribute. Omitted if empty. It can be set automatically with WithHostname.
 // Tests: total amount of test cases. Maps to the tests attribute. This field is
 // calculated automatically by Testsuites.MakeReport().
-// Failures: total amount of failures. Maps to the failures attribute. This
-// field is calculated automatically by Testsuites.MakeReport().
-// Errors: total amount of errors. Maps to the errors attribute. This field is
-// calculated automatically by Testsuites.MakeReport().
-// Time: optional duration of the test. Maps to the time attribute. Omitted if
-// empty.
+// Failures: total amount of failed test cases. Maps to the failures attribute.
+// This field is calculated automatically by Testsuites.MakeReport() according
+// to CountMode.
+// Errors: total amount of test cases with errors. Maps to the errors
+// attribute. This field is calculated automatically by Testsuites.MakeReport()
+// according to CountMode.
+// Skipped: total amount of skipped test cases. Maps to the skipped attribute.
+// This field is calculated automatically by Testsuites.MakeReport().
+// Time: optional duration of the test. Maps to the time attribute, written in
+// seconds. Omitted if empty. This field is calculated automatically by
+// Testsuites.MakeReport(): it is WallTime if set, otherwise the sum of the suite
+// times.
+// Properties: optional report properties. Maps to the properties tag. Omitted
+// if nil.
 // TestSuites: test suites. Each element maps to its own testsuite tag.
+// CountMode: how failures and errors are counted. Defaults to CountTestCases.
+// Not written to the report.
+// Sanitizer: optional Sanitizer applied to all text when the report is
+// rendered. Not written to the report.
+// WallTime: optional wall-clock duration of the whole run. Set by End(). Not
+// written to the report.
+// Precision: number of decimal places of the time attributes. Values lower
+// than 1 are treated as 1. DefaultDurationPrecision is used if 0. Not written
+// to the report.
 type TestSuites struct {
-	XMLName    xml.Name      `xml:"testsuites"`
-	ID         string        `xml:"id,attr,omitempty"`
-	Name       string        `xml:"name,attr,omitempty"`
-	Tests      int           `xml:"tests,attr"`
-	Failures   int           `xml:"failures,attr"`
-	Errors     int           `xml:"errors,attr"`
-	Time       time.Duration `xml:"time,attr,omitempty"`
-	TestSuites []*TestSuite  `xml:"testsuite,omitempty"`
+	XMLName    xml.Name     `xml:"testsuites" json:"-"`
+	ID         string       `xml:"id,attr,omitempty" json:"id,omitempty"`
+	Name       string       `xml:"name,attr,omitempty" json:"name,omitempty"`
+	Timestamp  Timestamp    `xml:"timestamp,attr" json:"timestamp"`
+	Hostname   string       `xml:"hostname,attr,omitempty" json:"hostname,omitempty"`
+	Tests      int          `xml:"tests,attr" json:"tests"`
+	Failures   int          `xml:"failures,attr" json:"failures"`
+	Errors     int          `xml:"errors,attr" json:"errors"`
+	Skipped    int          `xml:"skipped,attr" json:"skipped"`
+	Time       Duration     `xml:"time,attr,omitempty" json:"time,omitempty"`
+	Properties *Properties  `xml:"properties,omitempty" json:"properties,omitempty"`
+	TestSuites []*TestSuite `xml:"testsuite,omitempty" json:"testsuites,omitempty"`
+	CountMode  CountMode    `xml:"-" json:"-"`
+	Sanitizer  *Sanitizer   `xml:"-" json:"-"`
+	WallTime   Duration     `xml:"-" json:"-"`
+	Precision  int          `xml:"-" json:"-"`
+	startTime  time.Time    `xml:"-" json:"-"`
+	mu         sync.RWMutex
 }
 
 // NewTestSuites creates a new TestSuites with the given id and name
-func NewTestSuites(id string, name string) *TestSuites {
+func NewTestSuites(id string, name string, opts ...SuiteOption) *TestSuites {
 	return &TestSuites{
 		ID:       id,
 		Name:     name,
+		Hostname: newSuiteOptions(opts).hostnameValue(),
 		Tests:    0,
 		Failures: 0,
 		Errors:   0,
+		Skipped:  0,
 	}
 }
 
 // NewAnonymousTestSuites returns an empty TestSuites
-func NewAnonymousTestSuites() *TestSuites {
+func NewAnonymousTestSuites(opts ...SuiteOption) *TestSuites {
 	return &TestSuites{
+		Hostname: newSuiteOptions(opts).hostnameValue(),
 		Tests:    0,
 		Failures: 0,
 		Errors:   0,
+		Skipped:  0,
 	}
 }
 
 // AddTestSuite add a TestSuite to the TestSuites. If the test suite has an ID,
 // it must be unique within the suites. If it isn't an error is returned.
 func (suites *TestSuites) AddTestSuite(suite *TestSuite) error {
+	suites.mu.Lock()
+	defer suites.mu.Unlock()
+
 	if len(suite.ID) > 0 {
 		for _, s := range suites.TestSuites {
 			if s.ID == suite.ID {
@@ -74,32 +113,78 @@ func (suites *TestSuites) AddTestSuite(suite *TestSuite) error {
 // MakeReport generates the report XML as a slice of bytes. It is useful for any
 // output other than generating a file. For saving the report as a file you
 // should use SaveReport instead. All values are automatically calculated when
-// calling this method.
+// calling this method. It is safe to call it while the report is being built
+// from other goroutines.
 func (suites *TestSuites) MakeReport() ([]byte, error) {
-	suites.resolve()
-	content, err := xml.MarshalIndent(suites, "", "    ")
+	return IndentedXML.Format(suites)
+}
 
-	if err != nil {
-		return []byte{}, err
+// rendered calculates all values and returns the snapshot of the report that
+// must be rendered, sanitized if there is a Sanitizer
+func (suites *TestSuites) rendered() *TestSuites {
+	snapshot := suites.Snapshot()
+	if snapshot.Sanitizer != nil {
+		snapshot.Sanitizer.testSuites(snapshot)
 	}
 
-	return []byte(xml.Header + string(content)), nil
+	return snapshot
 }
 
-// SaveReport saves the report XMl in the given file name with the 644
-// permission settings. All values are automatically calculated when calling
-// this method.
-func (suites *TestSuites) SaveReport(filename string) error {
-	content, err := suites.MakeReport()
+// precision returns the number of decimal places of the report time attributes
+func (suites *TestSuites) precision() int {
+	if suites.Precision == 0 {
+		return DefaultDurationPrecision
+	}
+
+	return suites.Precision
+}
+
+// SaveReport saves the report in the given file name with the 644 permission
+// settings. The format is chosen by the file extension, see FormatterForFile,
+// unless WithFormatter is given. The way the file is written can be changed
+// with other options, eg: WithAtomicWrite. All values are automatically
+// calculated when calling this method.
+func (suites *TestSuites) SaveReport(filename string, opts ...SaveOption) error {
+	options := newSaveOptions(opts)
+	formatter := options.formatter
+	if formatter == nil {
+		formatter = FormatterForFile(filename)
+	}
+
+	content, err := formatter.Format(suites)
 	if err != nil {
 		return err
 	}
 
-	return os.WriteFile(filename, content, 0644)
+	return writeFile(filename, content, options)
+}
+
+// SetProperty sets the value of the report property with the given name
+func (suites *TestSuites) SetProperty(name string, value string) {
+	suites.mu.Lock()
+	defer suites.mu.Unlock()
+
+	if suites.Properties == nil {
+		suites.Properties = NewProperties()
+	}
+
+	suites.Properties.Set(name, value)
+}
+
+// GetProperty returns the value of the report property with the given name and
+// whether it exists
+func (suites *TestSuites) GetProperty(name string) (string, bool) {
+	suites.mu.RLock()
+	defer suites.mu.RUnlock()
+
+	return suites.Properties.Get(name)
 }
 
 // RemoveTestSuite removes a suite with the given id if it exists.
 func (suites *TestSuites) RemoveTestSuite(id string) {
+	suites.mu.Lock()
+	defer suites.mu.Unlock()
+
 	for i, suite := range suites.TestSuites {
 		if suite.ID == id {
 			suites.TestSuites = append(suites.TestSuites[:i], suites.TestSuites[i+1:]...)
@@ -108,37 +193,86 @@ func (suites *TestSuites) RemoveTestSuite(id string) {
 	}
 }
 
+// Start sets the time the tests started. If the report has no timestamp, it is
+// set to the current time.
+func (suites *TestSuites) Start() {
+	suites.mu.Lock()
+	defer suites.mu.Unlock()
+
+	suites.startTime = time.Now()
+	if suites.Timestamp.IsZero() {
+		suites.Timestamp = Timestamp(suites.startTime)
+	}
+}
+
+// End sets the wall-clock duration of the tests, which takes precedence over
+// the sum of the suite times
+func (suites *TestSuites) End() {
+	suites.mu.Lock()
+	defer suites.mu.Unlock()
+
+	suites.WallTime = Duration(time.Since(suites.startTime))
+}
+
+// SummedTime returns the sum of the suite times, regardless of WallTime. The
+// time of each suite is its WallTime if set, otherwise the sum of its test case
+// times.
+func (suites *TestSuites) SummedTime() Duration {
+	defer suites.rlockAll()()
+
+	var sum Duration
+	for _, suite := range suites.TestSuites {
+		sum += suite.totalTime()
+	}
+
+	return sum
+}
+
 // resolve calculates all the automatically calculated values (total time,
-// amount of tests, errors, etc.). This method resets the values before each
-// calculation so it is safe to call it multiple times.
+// amount of tests, errors, etc.), including the ones of nested suites. This
+// method resets the values before each calculation so it is safe to call it
+// multiple times. Timestamps are only set if they are empty.
 func (suites *TestSuites) resolve() {
 	suites.reset()
+	timestamp := Timestamp{}
 	for _, suite := range suites.TestSuites {
-		for _, testCase := range suite.TestCases {
-			suite.Tests++
-			suite.Failures += len(testCase.Failures)
-			suite.Errors += len(testCase.Errors)
-			suite.Time += testCase.Time
+		suite.resolve(suites.CountMode)
+
+		if suite.Timestamp.before(timestamp) {
+			timestamp = suite.Timestamp
 		}
 
-		suites.Tests += suite.Tests
-		suites.Failures += suite.Failures
-		suites.Errors += suite.Errors
-		suites.Time += suite.Time
+		suites.count(suite)
+	}
+
+	suites.resolveTime()
+	if suites.Timestamp.IsZero() {
+		suites.Timestamp = timestamp
 	}
 }
 
-// reset sets all automatically calculated values to 0
-func (suites *TestSuites) reset() {
-	for _, suite := range suites.TestSuites {
-		suite.Tests = 0
-		suite.Failures = 0
-		suite.Errors = 0
-		suite.Time = 0
+// resolveTime sets Time to WallTime if it was measured. Time must already be
+// the sum of the suite times.
+func (suites *TestSuites) resolveTime() {
+	if suites.WallTime != 0 {
+		suites.Time = suites.WallTime
 	}
+}
+
+// count adds the values of an already calculated suite to the totals
+func (suites *TestSuites) count(suite *TestSuite) {
+	suites.Tests += suite.Tests
+	suites.Failures += suite.Failures
+	suites.Errors += suite.Errors
+	suites.Skipped += suite.Skipped
+	suites.Time += suite.Time
+}
 
+// reset sets all automatically calculated values to 0
+func (suites *TestSuites) reset() {
 	suites.Tests = 0
 	suites.Failures = 0
 	suites.Errors = 0
+	suites.Skipped = 0
 	suites.Time = 0
 }
@@ -1,9 +1,11 @@
 package report
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -62,6 +64,18 @@ func TestAddTestSuite_Error(t *testing.T) {
 	assert.Equal(t, 2, len(actual.TestSuites))
 }
 
+func TestTestSuitesProperty(t *testing.T) {
+	actual := NewAnonymousTestSuites()
+
+	_, ok := actual.GetProperty("name")
+	assert.False(t, ok)
+
+	actual.SetProperty("name", "value")
+	value, ok := actual.GetProperty("name")
+	assert.True(t, ok)
+	assert.Equal(t, "value", value)
+}
+
 func TestRemoveTestSuite(t *testing.T) {
 	actual := NewAnonymousTestSuites()
 	err := actual.AddTestSuite(NewTestSuite("id1", "name"))
@@ -121,8 +135,75 @@ func TestMakeReport(t *testing.T) {
 	assert.Equal(t, string(expected), string(actual))
 }
 
+func TestMakeReport_Skipped(t *testing.T) {
+	suites := NewTestSuites("id", "name")
+	suite := NewTestSuite("suite", "suite")
+
+	err := suite.AddTestCase(NewTestCase("case#1", "case 1", "class"))
+	assert.Nil(t, err)
+
+	skipped := NewTestCase("case#2", "case 2", "class")
+	skipped.Skip("skip message")
+	err = suite.AddTestCase(skipped)
+	assert.Nil(t, err)
+
+	err = suites.AddTestSuite(suite)
+	assert.Nil(t, err)
+
+	actual, err := suites.MakeReport()
+	expected := mustLoadFile("make_report_skipped_expected.xml")
+
+	assert.Nil(t, err)
+	assert.Equal(t, string(expected), string(actual))
+}
+
+func TestMakeReport_Properties(t *testing.T) {
+	suites := NewTestSuites("id", "name")
+	suites.SetProperty("version", "1.0.0")
+
+	suite := NewTestSuite("suite", "suite")
+	suite.SetProperty("b", "2")
+	suite.SetProperty("a", "1")
+
+	testCase := NewTestCase("case", "case", "class")
+	testCase.SetProperty("attempt", "1")
+
+	err := suite.AddTestCase(testCase)
+	assert.Nil(t, err)
+	err = suites.AddTestSuite(suite)
+	assert.Nil(t, err)
+
+	actual, err := suites.MakeReport()
+	expected := mustLoadFile("make_report_properties_expected.xml")
+
+	assert.Nil(t, err)
+	assert.Equal(t, string(expected), string(actual))
+}
+
+func TestMakeReport_Output(t *testing.T) {
+	suites := NewTestSuites("id", "name")
+	suite := NewTestSuite("suite", "suite")
+
+	testCase := NewTestCase("case", "case", "class")
+	fmt.Fprint(testCase.SystemOutWriter(), "case output")
+	fmt.Fprint(testCase.SystemErrWriter(), "case error")
+
+	err := suite.AddTestCase(testCase)
+	assert.Nil(t, err)
+	fmt.Fprint(suite.SystemOutWriter(), "suite output")
+	fmt.Fprint(suite.SystemErrWriter(), "suite error")
+	err = suites.AddTestSuite(suite)
+	assert.Nil(t, err)
+
+	actual, err := suites.MakeReport()
+	expected := mustLoadFile("make_report_output_expected.xml")
+
+	assert.Nil(t, err)
+	assert.Equal(t, string(expected), string(actual))
+}
+
 func TestResolve(t *testing.T) {
-	suites := TestSuites{
+	suites := &TestSuites{
 		Tests:    0,
 		Failures: 0,
 		Errors:   0,
@@ -173,15 +254,21 @@ func TestResolve(t *testing.T) {
 					{
 						Time: 31,
 					},
+					{
+						Skipped: &Skipped{
+							Message: "m",
+						},
+					},
 				},
 			},
 		},
 	}
 
-	resolved := TestSuites{
-		Tests:    5,
+	resolved := &TestSuites{
+		Tests:    6,
 		Failures: 2,
 		Errors:   2,
+		Skipped:  1,
 		Time:     100,
 		TestSuites: []*TestSuite{
 			{
@@ -226,13 +313,19 @@ func TestResolve(t *testing.T) {
 			},
 			{
 				Time:     31,
-				Tests:    1,
+				Tests:    2,
 				Failures: 0,
 				Errors:   0,
+				Skipped:  1,
 				TestCases: []*TestCase{
 					{
 						Time: 31,
 					},
+					{
+						Skipped: &Skipped{
+							Message: "m",
+						},
+					},
 				},
 			},
 		},
@@ -245,6 +338,115 @@ func TestResolve(t *testing.T) {
 	assert.Equal(t, resolved, suites)
 }
 
+func TestResolve_Timestamp(t *testing.T) {
+	start := time.Date(2021, 3, 4, 15, 4, 5, 0, time.UTC)
+	fixed := Timestamp(start.Add(-time.Hour))
+
+	first := NewTestCase("case#1", "case 1", "")
+	first.startTime = start.Add(time.Minute)
+	second := NewTestCase("case#2", "case 2", "")
+	second.startTime = start
+	notStarted := NewTestCase("case#3", "case 3", "")
+
+	suite1 := NewTestSuite("suite#1", "suite 1")
+	suite1.TestCases = []*TestCase{first, notStarted, second}
+	suite2 := NewTestSuite("suite#2", "suite 2")
+	suite2.Timestamp = fixed
+	suite3 := NewTestSuite("suite#3", "suite 3")
+	suite3.TestCases = []*TestCase{notStarted}
+
+	suites := NewTestSuites("id", "name")
+	suites.TestSuites = []*TestSuite{suite1, suite2, suite3}
+	suites.resolve()
+
+	assert.Equal(t, Timestamp(start), suite1.Timestamp)
+	assert.Equal(t, fixed, suite2.Timestamp)
+	assert.True(t, suite3.Timestamp.IsZero())
+	assert.Equal(t, fixed, suites.Timestamp)
+
+	content, err := suites.MakeReport()
+	assert.Nil(t, err)
+	assert.Contains(t, string(content), `<testsuite id="suite#1" name="suite 1" timestamp="2021-03-04T15:04:05Z"`)
+	assert.Contains(t, string(content), `<testsuite id="suite#3" name="suite 3" tests="1"`)
+}
+
+func TestResolve_WallTime(t *testing.T) {
+	suite1 := NewTestSuite("suite#1", "suite 1")
+	suite1.TestCases = []*TestCase{{Time: 1000}, {Time: 2000}}
+	suite1.WallTime = 2500
+	suite2 := NewTestSuite("suite#2", "suite 2")
+	suite2.TestCases = []*TestCase{{Time: 4000}}
+
+	suites := NewTestSuites("id", "name")
+	suites.TestSuites = []*TestSuite{suite1, suite2}
+	suites.resolve()
+
+	assert.Equal(t, Duration(2500), suite1.Time)
+	assert.Equal(t, Duration(4000), suite2.Time)
+	assert.Equal(t, Duration(6500), suites.Time)
+	assert.Equal(t, Duration(6500), suites.SummedTime())
+
+	suites.WallTime = 5000
+	suites.resolve()
+
+	assert.Equal(t, Duration(5000), suites.Time)
+	assert.Equal(t, Duration(6500), suites.SummedTime())
+}
+
+func TestResolve_Nested(t *testing.T) {
+	suites, err := LoadReport(filepath.Join("testdata", "flatten_nested.xml"))
+	assert.Nil(t, err)
+	suites.TestSuites[0].WallTime = Duration(5 * time.Second)
+	suites.resolve()
+
+	api := suites.TestSuites[0]
+	users := api.TestSuites[0]
+	assert.Equal(t, 2, users.TestSuites[0].Tests)
+	assert.Equal(t, 3, users.Tests)
+	assert.Equal(t, 1, users.Failures)
+	assert.Equal(t, Duration(time.Second), users.Time)
+	assert.Equal(t, 4, api.Tests)
+	assert.Equal(t, 1, api.Failures)
+	assert.Equal(t, Duration(5*time.Second), api.Time)
+	assert.Equal(t, 4, suites.Tests)
+	assert.Equal(t, Duration(5*time.Second), suites.Time)
+	assert.Equal(t, 1, suites.FailureElements())
+	assert.Equal(t, Duration(time.Second), users.SummedTime())
+
+	content, err := suites.MakeReport()
+	assert.Nil(t, err)
+	assert.Contains(t, string(content), `
+            <testsuite id="create" name="create" time="1.000" tests="2" failures="1" errors="0" skipped="0">`)
+}
+
+func TestTestSuitesStartEnd(t *testing.T) {
+	suites := NewTestSuites("id", "name")
+	suites.Start()
+	time.Sleep(time.Millisecond)
+	suites.End()
+
+	assert.False(t, suites.Timestamp.IsZero())
+	assert.GreaterOrEqual(t, suites.WallTime, Duration(time.Millisecond))
+}
+
+// newTestSuites returns an anonymous report with a suite with the given ID and
+// a test case for each of the given test case IDs
+func newTestSuites(t *testing.T, suiteID string, caseIDs ...string) *TestSuites {
+	t.Helper()
+
+	suite := NewTestSuite(suiteID, "name")
+	for _, id := range caseIDs {
+		err := suite.AddTestCase(NewTestCase(id, "name", "class"))
+		assert.Nil(t, err)
+	}
+
+	suites := NewAnonymousTestSuites()
+	err := suites.AddTestSuite(suite)
+	assert.Nil(t, err)
+
+	return suites
+}
+
 func mustLoadFile(name string) []byte {
 	b, err := os.ReadFile(filepath.Join("testdata", name))
 	if err != nil {
@@ -0,0 +1,105 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimestampLayout is the ISO-8601 layout used when writing timestamp
+// attributes, eg: timestamp="2021-03-04T15:04:05Z"
+const TimestampLayout = time.RFC3339
+
+// timestampLayouts are the layouts accepted when reading a timestamp
+// attribute. The Ant schema doesn't include the time zone, so timestamps
+// without it are read as UTC.
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+}
+
+// Timestamp is a time.Time that maps to a timestamp attribute. It is written in
+// ISO-8601 using TimestampLayout. A zero Timestamp is omitted.
+type Timestamp time.Time
+
+// NewTimestamp returns a Timestamp for the given time
+func NewTimestamp(t time.Time) Timestamp {
+	return Timestamp(t)
+}
+
+// Time returns the timestamp as a time.Time
+func (t Timestamp) Time() time.Time {
+	return time.Time(t)
+}
+
+// IsZero reports whether the timestamp is the zero time
+func (t Timestamp) IsZero() bool {
+	return t.Time().IsZero()
+}
+
+// String returns the timestamp formatted with TimestampLayout, or an empty
+// string if it is zero
+func (t Timestamp) String() string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.Time().Format(TimestampLayout)
+}
+
+// MarshalXMLAttr implements xml.MarshalerAttr. The attribute is omitted if the
+// timestamp is zero.
+func (t Timestamp) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	if t.IsZero() {
+		return xml.Attr{}, nil
+	}
+
+	return xml.Attr{Name: name, Value: t.String()}, nil
+}
+
+// MarshalJSON implements json.Marshaler. The timestamp is written as a string
+// formatted with TimestampLayout, or null if it is zero.
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+
+	return []byte(strconv.Quote(t.String())), nil
+}
+
+// UnmarshalXMLAttr implements xml.UnmarshalerAttr
+func (t *Timestamp) UnmarshalXMLAttr(attr xml.Attr) error {
+	parsed, err := ParseTimestamp(attr.Value)
+	if err != nil {
+		return err
+	}
+
+	*t = parsed
+	return nil
+}
+
+// ParseTimestamp parses a timestamp attribute value. Both ISO-8601 values with
+// and without a time zone are accepted, the latter are read as UTC. An empty
+// value is parsed as the zero Timestamp.
+func ParseTimestamp(s string) (Timestamp, error) {
+	s = strings.TrimSpace(s)
+	if len(s) == 0 {
+		return Timestamp{}, nil
+	}
+
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return Timestamp(t), nil
+		}
+	}
+
+	return Timestamp{}, fmt.Errorf("cannot parse timestamp %q: not in ISO-8601", s)
+}
+
+// before reports whether t is set and before other, or other is zero
+func (t Timestamp) before(other Timestamp) bool {
+	return !t.IsZero() && (other.IsZero() || t.Time().Before(other.Time()))
+}
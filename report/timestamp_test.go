@@ -0,0 +1,67 @@
+package report
+
+import (
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimestampString(t *testing.T) {
+	ts := NewTimestamp(time.Date(2021, 3, 4, 15, 4, 5, 600, time.UTC))
+
+	assert.Equal(t, "2021-03-04T15:04:05Z", ts.String())
+	assert.Equal(t, "", Timestamp{}.String())
+}
+
+func TestTimestampMarshalXMLAttr(t *testing.T) {
+	zone := time.FixedZone("BRT", -3*60*60)
+	suite := &TestSuite{Timestamp: NewTimestamp(time.Date(2021, 3, 4, 15, 4, 5, 0, zone))}
+
+	actual, err := xml.Marshal(suite)
+	assert.Nil(t, err)
+	assert.Equal(
+		t,
+		`<TestSuite timestamp="2021-03-04T15:04:05-03:00" tests="0" failures="0" errors="0" skipped="0"></TestSuite>`,
+		string(actual),
+	)
+
+	actual, err = xml.Marshal(&TestSuite{})
+	assert.Nil(t, err)
+	assert.NotContains(t, string(actual), "timestamp")
+}
+
+func TestTimestampUnmarshalXMLAttr(t *testing.T) {
+	actual := &TestSuite{}
+	err := xml.Unmarshal([]byte(`<testsuite timestamp="2021-03-04T15:04:05Z"></testsuite>`), actual)
+
+	assert.Nil(t, err)
+	assert.True(t, time.Date(2021, 3, 4, 15, 4, 5, 0, time.UTC).Equal(actual.Timestamp.Time()))
+}
+
+func TestParseTimestamp(t *testing.T) {
+	expected := time.Date(2021, 3, 4, 15, 4, 5, 0, time.UTC)
+	tests := map[string]time.Time{
+		"":                              {},
+		"2021-03-04T15:04:05Z":          expected,
+		"2021-03-04T12:04:05-03:00":     expected,
+		"2021-03-04T15:04:05":           expected,
+		"2021-03-04 15:04:05":           expected,
+		"2021-03-04T15:04:05.250":       expected.Add(250 * time.Millisecond),
+		"2021-03-04T15:04:05.250+00:00": expected.Add(250 * time.Millisecond),
+	}
+
+	for value, expected := range tests {
+		actual, err := ParseTimestamp(value)
+
+		assert.Nil(t, err, value)
+		assert.True(t, expected.Equal(actual.Time()), value)
+	}
+}
+
+func TestParseTimestamp_Error(t *testing.T) {
+	_, err := ParseTimestamp("yesterday")
+
+	assert.NotNil(t, err)
+}
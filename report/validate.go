@@ -0,0 +1,364 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Schema is a set of JUnit rules a report can be validated against
+type Schema int
+
+const (
+	// SchemaGitLab checks the rules shared by most JUnit consumers, such as
+	// GitLab and Bitbucket: legal XML characters, unique IDs, named test cases
+	// and properties, and positive times.
+	SchemaGitLab Schema = iota
+	// SchemaAnt checks the rules of SchemaGitLab plus the ones of the
+	// Jenkins/Ant XSD: test suites and test cases must have a name, test cases
+	// must have a class name, a test case can have at most one failure or error
+	// and can't be both skipped and failed, and test cases can't have
+	// properties or flaky and rerun failures and errors. Test suites can't be
+	// nested and test cases, failures, and errors can't have a file and line.
+	SchemaAnt
+)
+
+// ValidationError is a rule violation found by TestSuites.Validate. Suite and
+// Case are the indexes of the offending suite and test case, or -1 when the
+// violation isn't in a suite or test case. For nested suites, Suite is the index
+// in the parent suite and Parents the indexes of the parent suites, starting at
+// the top level. Field is the attribute or tag that breaks the rule.
+type ValidationError struct {
+	Parents   []int
+	Suite     int
+	SuiteID   string
+	SuiteName string
+	Case      int
+	CaseID    string
+	CaseName  string
+	Field     string
+	Message   string
+}
+
+// Error returns the violation with the location of the offending element
+func (e *ValidationError) Error() string {
+	location := []string{}
+	if e.Suite >= 0 {
+		suite := describeElement("suite", e.Suite, e.SuiteID, e.SuiteName)
+		for i := len(e.Parents) - 1; i >= 0; i-- {
+			suite = fmt.Sprintf("suite #%d > %s", e.Parents[i], suite)
+		}
+		location = append(location, suite)
+	}
+
+	if e.Case >= 0 {
+		location = append(location, describeElement("case", e.Case, e.CaseID, e.CaseName))
+	}
+
+	location = append(location, e.Field)
+	return strings.Join(location, ": ") + ": " + e.Message
+}
+
+// ValidationErrors is the list of violations returned by TestSuites.Validate
+type ValidationErrors []*ValidationError
+
+// Error returns all violations, one per line
+func (errs ValidationErrors) Error() string {
+	messages := make([]string, 0, len(errs))
+	for _, e := range errs {
+		messages = append(messages, e.Error())
+	}
+
+	return strings.Join(messages, "\n")
+}
+
+// Validate checks the report against the SchemaGitLab rules. It returns nil if
+// the report is valid or ValidationErrors with every violation found.
+func (suites *TestSuites) Validate() error {
+	return suites.ValidateSchema(SchemaGitLab)
+}
+
+// ValidateSchema checks the report against the rules of the given schema. It
+// returns nil if the report is valid or ValidationErrors with every violation
+// found.
+func (suites *TestSuites) ValidateSchema(schema Schema) error {
+	defer suites.rlockAll()()
+
+	v := &validator{schema: schema}
+	v.validateTestSuites(suites)
+
+	if len(v.errs) == 0 {
+		return nil
+	}
+
+	return v.errs
+}
+
+// validator accumulates the violations found while walking a report
+type validator struct {
+	schema     Schema
+	errs       ValidationErrors
+	parents    []int
+	suiteIndex int
+	caseIndex  int
+	suite      *TestSuite
+	testCase   *TestCase
+}
+
+func (v *validator) validateTestSuites(suites *TestSuites) {
+	v.suiteIndex = -1
+	v.caseIndex = -1
+
+	v.checkText("id", suites.ID)
+	v.checkText("name", suites.Name)
+	v.checkText("hostname", suites.Hostname)
+	v.checkTime(suites.Time)
+	v.checkProperties(suites.Properties)
+
+	v.validateTestSuiteList(suites.TestSuites)
+}
+
+// validateTestSuiteList validates sibling suites, which must have unique IDs
+func (v *validator) validateTestSuiteList(suites []*TestSuite) {
+	ids := map[string]bool{}
+	for i, suite := range suites {
+		v.suiteIndex = i
+		v.suite = suite
+		v.caseIndex = -1
+		v.testCase = nil
+
+		if len(suite.ID) > 0 {
+			if ids[suite.ID] {
+				v.add("id", "duplicated suite ID")
+			}
+			ids[suite.ID] = true
+		}
+
+		v.validateTestSuite(suite)
+	}
+}
+
+func (v *validator) validateTestSuite(suite *TestSuite) {
+	v.checkText("id", suite.ID)
+	v.checkText("name", suite.Name)
+	v.checkText("package", suite.Package)
+	v.checkText("hostname", suite.Hostname)
+	v.checkText("system-out", suite.SystemOut)
+	v.checkText("system-err", suite.SystemErr)
+	v.checkTime(suite.Time)
+	v.checkProperties(suite.Properties)
+
+	if v.schema == SchemaAnt && len(suite.Name) == 0 {
+		v.add("name", "is required")
+	}
+
+	if v.schema == SchemaAnt && len(suite.TestSuites) > 0 {
+		v.add("testsuite", "nested test suites are not allowed")
+	}
+
+	ids := map[string]bool{}
+	for i, testCase := range suite.TestCases {
+		v.caseIndex = i
+		v.testCase = testCase
+
+		if len(testCase.ID) > 0 {
+			if ids[testCase.ID] {
+				v.add("id", "duplicated test case ID")
+			}
+			ids[testCase.ID] = true
+		}
+
+		v.validateTestCase(testCase)
+	}
+
+	if len(suite.TestSuites) == 0 {
+		return
+	}
+
+	v.parents = append(v.parents, v.suiteIndex)
+	v.validateTestSuiteList(suite.TestSuites)
+	v.parents = v.parents[:len(v.parents)-1]
+}
+
+func (v *validator) validateTestCase(testCase *TestCase) {
+	v.checkText("id", testCase.ID)
+	v.checkText("name", testCase.Name)
+	v.checkText("classname", testCase.Classname)
+	v.checkText("file", testCase.File)
+	v.checkText("content", testCase.Content)
+	v.checkText("system-out", testCase.SystemOut)
+	v.checkText("system-err", testCase.SystemErr)
+	v.checkTime(testCase.Time)
+	v.checkProperties(testCase.Properties)
+
+	if len(testCase.Name) == 0 {
+		v.add("name", "is required")
+	}
+
+	if testCase.Skipped != nil {
+		v.checkText("skipped", testCase.Skipped.Message)
+		v.checkText("skipped", testCase.Skipped.Content)
+	}
+
+	for _, f := range testCase.Failures {
+		v.checkText("failure", f.Message)
+		v.checkText("failure", f.Type)
+		v.checkText("failure", f.File)
+		v.checkText("failure", f.Content)
+	}
+
+	for _, e := range testCase.Errors {
+		v.checkText("error", e.Message)
+		v.checkText("error", e.Type)
+		v.checkText("error", e.File)
+		v.checkText("error", e.Content)
+	}
+
+	v.checkReruns("flakyFailure", testCase.FlakyFailures)
+	v.checkReruns("flakyError", testCase.FlakyErrors)
+	v.checkReruns("rerunFailure", testCase.RerunFailures)
+	v.checkReruns("rerunError", testCase.RerunErrors)
+
+	if v.schema != SchemaAnt {
+		return
+	}
+
+	if len(testCase.Classname) == 0 {
+		v.add("classname", "is required")
+	}
+
+	if len(testCase.Failures)+len(testCase.Errors) > 1 {
+		v.add("failure", "a test case can have at most one failure or error")
+	}
+
+	if testCase.Skipped != nil && len(testCase.Failures)+len(testCase.Errors) > 0 {
+		v.add("skipped", "a skipped test case can't have failures or errors")
+	}
+
+	if testCase.Properties != nil {
+		v.add("properties", "test case properties are not allowed")
+	}
+
+	if len(testCase.File) > 0 || testCase.Line != 0 {
+		v.add("file", "file and line attributes are not allowed")
+	}
+
+	for _, f := range testCase.Failures {
+		if len(f.File) > 0 || f.Line != 0 {
+			v.add("failure", "file and line attributes are not allowed")
+		}
+	}
+
+	for _, e := range testCase.Errors {
+		if len(e.File) > 0 || e.Line != 0 {
+			v.add("error", "file and line attributes are not allowed")
+		}
+	}
+
+	if len(testCase.FlakyFailures)+len(testCase.FlakyErrors)+len(testCase.RerunFailures)+len(testCase.RerunErrors) > 0 {
+		v.add("rerun", "flaky and rerun failures and errors are not allowed")
+	}
+}
+
+func (v *validator) checkReruns(field string, reruns []*Rerun) {
+	for _, r := range reruns {
+		v.checkText(field, r.Message)
+		v.checkText(field, r.Type)
+		v.checkText(field, r.StackTrace)
+		v.checkText(field, r.SystemOut)
+		v.checkText(field, r.SystemErr)
+		v.checkTime(r.Time)
+	}
+}
+
+func (v *validator) checkProperties(properties *Properties) {
+	if properties == nil {
+		return
+	}
+
+	for _, p := range properties.Properties {
+		if len(p.Name) == 0 {
+			v.add("property", "name is required")
+		}
+
+		v.checkText("property", p.Name)
+		v.checkText("property", p.Value)
+	}
+}
+
+func (v *validator) checkTime(time Duration) {
+	if time < 0 {
+		v.add("time", "must not be negative")
+	}
+}
+
+// checkText adds a violation if s isn't valid UTF-8 or contains characters
+// that are illegal in XML 1.0
+func (v *validator) checkText(field string, s string) {
+	if !utf8.ValidString(s) {
+		v.add(field, "is not valid UTF-8")
+		return
+	}
+
+	for i, r := range s {
+		if !isLegalXMLChar(r) {
+			v.add(field, fmt.Sprintf("illegal XML character %U at byte %d", r, i))
+			return
+		}
+	}
+}
+
+func (v *validator) add(field string, message string) {
+	e := &ValidationError{
+		Suite:   v.suiteIndex,
+		Case:    v.caseIndex,
+		Field:   field,
+		Message: message,
+	}
+
+	if len(v.parents) > 0 {
+		e.Parents = append([]int{}, v.parents...)
+	}
+
+	if v.suiteIndex >= 0 {
+		e.SuiteID = v.suite.ID
+		e.SuiteName = v.suite.Name
+	}
+
+	if v.caseIndex >= 0 {
+		e.CaseID = v.testCase.ID
+		e.CaseName = v.testCase.Name
+	}
+
+	v.errs = append(v.errs, e)
+}
+
+// isLegalXMLChar reports whether r is allowed by the XML 1.0 Char production
+func isLegalXMLChar(r rune) bool {
+	return r == 0x09 ||
+		r == 0x0A ||
+		r == 0x0D ||
+		(r >= 0x20 && r <= 0xD7FF) ||
+		(r >= 0xE000 && r <= 0xFFFD) ||
+		(r >= 0x10000 && r <= 0x10FFFF)
+}
+
+// describeElement returns a description of a suite or test case, eg:
+// suite #1 (ID=id, name=name)
+func describeElement(kind string, index int, id string, name string) string {
+	details := []string{}
+	if len(id) > 0 {
+		details = append(details, "ID="+id)
+	}
+
+	if len(name) > 0 {
+		details = append(details, "name="+name)
+	}
+
+	description := fmt.Sprintf("%s #%d", kind, index)
+	if len(details) > 0 {
+		description += " (" + strings.Join(details, ", ") + ")"
+	}
+
+	return description
+}
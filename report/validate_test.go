@@ -0,0 +1,213 @@
+package report
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate_Valid(t *testing.T) {
+	suites, err := LoadReport(filepath.Join("testdata", "validate_valid.xml"))
+	assert.Nil(t, err)
+
+	assert.Nil(t, suites.Validate())
+	assert.Nil(t, suites.ValidateSchema(SchemaAnt))
+}
+
+func TestValidate(t *testing.T) {
+	suites, err := LoadReport(filepath.Join("testdata", "validate_valid.xml"))
+	assert.Nil(t, err)
+	suites.Time = -1
+	suites.TestSuites = append(suites.TestSuites, NewTestSuite("suite#1", "suite 2"))
+	suite := suites.TestSuites[0]
+	suite.TestCases = append(suite.TestCases, NewTestCase("case#1", "", ""))
+	suite.TestCases[0].AddError(NewError("msg\x00", "", "\x1b[31mred\x1b[0m"))
+	suite.SetProperty("", "\xff")
+
+	err = suites.Validate()
+
+	var errs ValidationErrors
+	assert.True(t, errors.As(err, &errs))
+	assert.Equal(t, ValidationErrors{
+		{
+			Suite:   -1,
+			Case:    -1,
+			Field:   "time",
+			Message: "must not be negative",
+		},
+		{
+			Suite:     0,
+			SuiteID:   "suite#1",
+			SuiteName: "suite 1",
+			Case:      -1,
+			Field:     "property",
+			Message:   "name is required",
+		},
+		{
+			Suite:     0,
+			SuiteID:   "suite#1",
+			SuiteName: "suite 1",
+			Case:      -1,
+			Field:     "property",
+			Message:   "is not valid UTF-8",
+		},
+		{
+			Suite:     0,
+			SuiteID:   "suite#1",
+			SuiteName: "suite 1",
+			Case:      0,
+			CaseID:    "case#1",
+			CaseName:  "case 1",
+			Field:     "error",
+			Message:   "illegal XML character U+0000 at byte 3",
+		},
+		{
+			Suite:     0,
+			SuiteID:   "suite#1",
+			SuiteName: "suite 1",
+			Case:      0,
+			CaseID:    "case#1",
+			CaseName:  "case 1",
+			Field:     "error",
+			Message:   "illegal XML character U+001B at byte 0",
+		},
+		{
+			Suite:     0,
+			SuiteID:   "suite#1",
+			SuiteName: "suite 1",
+			Case:      1,
+			CaseID:    "case#1",
+			Field:     "id",
+			Message:   "duplicated test case ID",
+		},
+		{
+			Suite:     0,
+			SuiteID:   "suite#1",
+			SuiteName: "suite 1",
+			Case:      1,
+			CaseID:    "case#1",
+			Field:     "name",
+			Message:   "is required",
+		},
+		{
+			Suite:     1,
+			SuiteID:   "suite#1",
+			SuiteName: "suite 2",
+			Case:      -1,
+			Field:     "id",
+			Message:   "duplicated suite ID",
+		},
+	}, errs)
+}
+
+func TestValidate_Nested(t *testing.T) {
+	suites, err := LoadReport(filepath.Join("testdata", "validate_valid.xml"))
+	assert.Nil(t, err)
+	parent := suites.TestSuites[0]
+	assert.Nil(t, parent.AddTestSuite(NewTestSuite("nested#1", "nested 1")))
+	nested := NewTestSuite("nested#2", "nested 2")
+	nested.TestSuites = []*TestSuite{NewTestSuite("leaf", "leaf"), NewTestSuite("leaf", "leaf")}
+	assert.Nil(t, parent.AddTestSuite(nested))
+
+	err = suites.Validate()
+
+	assert.Equal(t, ValidationErrors{
+		{
+			Parents:   []int{0, 1},
+			Suite:     1,
+			SuiteID:   "leaf",
+			SuiteName: "leaf",
+			Case:      -1,
+			Field:     "id",
+			Message:   "duplicated suite ID",
+		},
+	}, err)
+
+	var errs ValidationErrors
+	assert.True(t, errors.As(suites.ValidateSchema(SchemaAnt), &errs))
+	assert.Equal(t, "suite #0 (ID=suite#1, name=suite 1): testsuite: nested test suites are not allowed", errs[0].Error())
+	assert.Equal(t, "testsuite", errs[1].Field)
+	assert.Equal(t, "id", errs[2].Field)
+}
+
+func TestValidateSchema_Ant(t *testing.T) {
+	suites, err := LoadReport(filepath.Join("testdata", "validate_valid.xml"))
+	assert.Nil(t, err)
+	suite := NewAnonymousTestSuite()
+	testCase := NewTestCase("", "name", "")
+	testCase.Skip("skip")
+	testCase.AddFailure(NewAnonymousFailure("failure"))
+	testCase.AddError(NewAnonymousError("error"))
+	testCase.SetProperty("name", "value")
+	testCase.AddFlakyFailure(NewRerun("msg", "", ""))
+	err = suite.AddTestCase(testCase)
+	assert.Nil(t, err)
+	err = suites.AddTestSuite(suite)
+	assert.Nil(t, err)
+
+	assert.Nil(t, suites.Validate())
+
+	err = suites.ValidateSchema(SchemaAnt)
+
+	var errs ValidationErrors
+	assert.True(t, errors.As(err, &errs))
+
+	fields := []string{}
+	for _, e := range errs {
+		fields = append(fields, e.Field)
+	}
+	assert.Equal(t, []string{"name", "classname", "failure", "skipped", "properties", "rerun"}, fields)
+}
+
+func TestValidateSchema_AntLocation(t *testing.T) {
+	suites, err := LoadReport(filepath.Join("testdata", "validate_valid.xml"))
+	assert.Nil(t, err)
+	suites.TestSuites[0].TestCases[0].Failures[0].SetLocation("file.go", 1)
+	suites.TestSuites[0].TestCases[0].SetLocation("file_test.go", 0)
+
+	assert.Nil(t, suites.Validate())
+
+	var errs ValidationErrors
+	assert.True(t, errors.As(suites.ValidateSchema(SchemaAnt), &errs))
+	assert.Equal(t, 2, len(errs))
+	assert.Equal(t, "file", errs[0].Field)
+	assert.Equal(t, "failure", errs[1].Field)
+	assert.Equal(t, "file and line attributes are not allowed", errs[1].Message)
+}
+
+func TestValidationErrorError(t *testing.T) {
+	err := &ValidationError{
+		Suite:     1,
+		SuiteID:   "suite#1",
+		SuiteName: "suite 1",
+		Case:      2,
+		CaseName:  "case 2",
+		Field:     "name",
+		Message:   "is required",
+	}
+
+	assert.Equal(t, "suite #1 (ID=suite#1, name=suite 1): case #2 (name=case 2): name: is required", err.Error())
+
+	err = &ValidationError{
+		Suite:   -1,
+		Case:    -1,
+		Field:   "time",
+		Message: "must not be negative",
+	}
+
+	assert.Equal(t, "time: must not be negative", err.Error())
+	assert.Equal(t, "time: must not be negative\ntime: must not be negative", ValidationErrors{err, err}.Error())
+
+	err = &ValidationError{
+		Parents:   []int{0, 3},
+		Suite:     1,
+		SuiteName: "leaf",
+		Case:      -1,
+		Field:     "id",
+		Message:   "duplicated suite ID",
+	}
+
+	assert.Equal(t, "suite #0 > suite #3 > suite #1 (name=leaf): id: duplicated suite ID", err.Error())
+}